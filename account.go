@@ -0,0 +1,221 @@
+package brandalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultAccountURL is the default WhoisXML API account balance URL.
+const defaultAccountURL = `https://user.whoisxmlapi.com/user-service/account-balance`
+
+// BrandAlertProduct is the product name of Brand Alert API in the account balance.
+const BrandAlertProduct = "Brand Alert API"
+
+// Account is an interface for WhoisXML API account balance.
+type Account interface {
+	// Balances returns the credits balances of all products of the account.
+	Balances(ctx context.Context) (Balances, *Response, error)
+}
+
+// Product is the WhoisXML API product.
+type Product struct {
+	// ID is the product ID.
+	ID int `json:"id"`
+
+	// Name is the product name, e.g. "Brand Alert API".
+	Name string `json:"name"`
+}
+
+// Balance is the credits balance of the product.
+type Balance struct {
+	// ProductID is the product ID.
+	ProductID int `json:"product_id"`
+
+	// Product is the product.
+	Product Product `json:"product"`
+
+	// Credits is the number of credits left.
+	Credits int `json:"credits"`
+}
+
+// Balances is the list of the product balances.
+type Balances []Balance
+
+// Get returns the balance of the product by its case-insensitive name.
+func (b Balances) Get(product string) (Balance, bool) {
+	for _, balance := range b {
+		if strings.EqualFold(balance.Product.Name, product) {
+			return balance, true
+		}
+	}
+
+	return Balance{}, false
+}
+
+// BrandAlert returns the balance of Brand Alert API.
+func (b Balances) BrandAlert() (Balance, bool) {
+	return b.Get(BrandAlertProduct)
+}
+
+// accountResponse is used for parsing the account balance response.
+type accountResponse struct {
+	Data Balances `json:"data"`
+
+	ErrorMessage
+}
+
+// accountServiceOp is the type implementing the Account interface.
+type accountServiceOp struct {
+	client  *Client
+	baseURL *url.URL
+}
+
+var _ Account = &accountServiceOp{}
+
+// Balances returns the credits balances of all products of the account. The
+// account of the key of the next request is checked without advancing the
+// rotation of the keys.
+func (service accountServiceOp) Balances(ctx context.Context) (balances Balances, resp *Response, err error) {
+	key, err := peekKey(ctx, service.client.keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get API key: %w", err)
+	}
+
+	u := *service.baseURL
+	q := u.Query()
+	q.Set("apiKey", key)
+	q.Set("output_format", "JSON")
+	u.RawQuery = q.Encode()
+
+	req, err := service.client.NewRequest(http.MethodGet, &u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b bytes.Buffer
+
+	httpResp, size, err := service.client.do(ctx, req, &b)
+
+	resp = &Response{
+		Response:         httpResp,
+		Body:             b.Bytes(),
+		CompressedSize:   size.compressed,
+		UncompressedSize: size.uncompressed,
+	}
+
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var accountResp accountResponse
+
+	if err := json.Unmarshal(resp.Body, &accountResp); err != nil {
+		if respErr := checkResponse(resp.Response); respErr != nil {
+			return nil, resp, respErr
+		}
+		return nil, resp, fmt.Errorf("cannot parse response: %w", err)
+	}
+
+	if accountResp.Message != nil || accountResp.Code != 0 {
+		return nil, resp, &ErrorMessage{
+			Code:    accountResp.Code,
+			Message: accountResp.Message,
+		}
+	}
+
+	if respErr := checkResponse(resp.Response); respErr != nil {
+		return nil, resp, respErr
+	}
+
+	return accountResp.Data, resp, nil
+}
+
+// ErrProductNotFound is returned by BudgetGuard when the account balance has
+// no Brand Alert API product.
+var ErrProductNotFound = errors.New("product not found in the account balance")
+
+// InsufficientCreditsError is returned by BudgetGuard when the product has
+// fewer credits than required.
+type InsufficientCreditsError struct {
+	Product    string
+	Credits    int
+	MinCredits int
+}
+
+// Error returns error message as a string.
+func (e *InsufficientCreditsError) Error() string {
+	return fmt.Sprintf("insufficient credits: %s has %d credits, at least %d required", e.Product, e.Credits, e.MinCredits)
+}
+
+// BudgetGuard is the BrandAlert refusing Purchase and RawData requests with
+// *InsufficientCreditsError when the account has fewer than MinCredits Brand
+// Alert API credits, or with ErrProductNotFound when the account has no Brand
+// Alert API balance. Preview requests are free and always allowed.
+type BudgetGuard struct {
+	BrandAlert
+
+	account Account
+
+	// MinCredits is the minimum number of credits required to purchase.
+	MinCredits int
+}
+
+var _ BrandAlert = &BudgetGuard{}
+
+// NewBudgetGuard creates the guard of the client checking the account balance
+// before every purchase.
+func NewBudgetGuard(client BrandAlert, account Account, minCredits int) *BudgetGuard {
+	return &BudgetGuard{BrandAlert: client, account: account, MinCredits: minCredits}
+}
+
+// Check returns *InsufficientCreditsError if the account has fewer than
+// MinCredits Brand Alert API credits, or the error wrapping ErrProductNotFound
+// if the balance has no Brand Alert API product.
+func (g *BudgetGuard) Check(ctx context.Context) error {
+	balances, _, err := g.account.Balances(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot check account balance: %w", err)
+	}
+
+	balance, ok := balances.BrandAlert()
+	if !ok {
+		return fmt.Errorf("%s: %w", BrandAlertProduct, ErrProductNotFound)
+	}
+	if balance.Credits < g.MinCredits {
+		return &InsufficientCreditsError{Product: BrandAlertProduct, Credits: balance.Credits, MinCredits: g.MinCredits}
+	}
+
+	return nil
+}
+
+// Purchase checks the balance and returns parsed Brand Alert API response.
+func (g *BudgetGuard) Purchase(
+	ctx context.Context,
+	includeSearchTerms *SearchTerms, excludeSearchTerms *SearchTerms,
+	opts ...Option,
+) (*BrandAlertResponse, *Response, error) {
+	if err := g.Check(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return g.BrandAlert.Purchase(ctx, includeSearchTerms, excludeSearchTerms, opts...)
+}
+
+// RawData checks the balance and returns raw Brand Alert API response.
+func (g *BudgetGuard) RawData(
+	ctx context.Context,
+	includeSearchTerms *SearchTerms, excludeSearchTerms *SearchTerms,
+	opts ...Option,
+) (*Response, error) {
+	if err := g.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	return g.BrandAlert.RawData(ctx, includeSearchTerms, excludeSearchTerms, opts...)
+}
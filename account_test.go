@@ -0,0 +1,220 @@
+package brandalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const balancesResponse = `{"data":[
+{"product_id":1,"product":{"id":1,"name":"WHOIS API"},"credits":500},
+{"product_id":17,"product":{"id":17,"name":"Brand Alert API"},"credits":12}]}`
+
+// TestAccountBalances tests the Balances function.
+func TestAccountBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", req.Method)
+		}
+
+		switch req.URL.Query().Get("apiKey") {
+		case apiKey:
+			_, _ = w.Write([]byte(balancesResponse))
+		case "invalid":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code":403,"messages":"Access restricted. Check the API key."}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`Internal Server Error`))
+		}
+	}))
+	defer server.Close()
+
+	accountURL, err := url.Parse(server.URL + "/user-service/account-balance")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		key         string
+		wantCredits int
+		wantErr     string
+	}{
+		{
+			name:        "ok",
+			key:         apiKey,
+			wantCredits: 12,
+		},
+		{
+			name:    "error message",
+			key:     "invalid",
+			wantErr: "API error: [403] [Access restricted. Check the API key.]",
+		},
+		{
+			name:    "unparsable",
+			key:     "broken",
+			wantErr: "API failed with status code: 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.key, ClientParams{HTTPClient: server.Client(), AccountBaseURL: accountURL})
+
+			balances, resp, err := client.Account.Balances(context.Background())
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if resp == nil || resp.StatusCode != http.StatusOK {
+				t.Errorf("Balances() response = %v, want 200 OK", resp)
+			}
+
+			balance, ok := balances.BrandAlert()
+			if !ok || balance.Credits != tt.wantCredits || balance.ProductID != 17 {
+				t.Errorf("BrandAlert() got = %+v, %v, want %d credits", balance, ok, tt.wantCredits)
+			}
+
+			if balance, ok := balances.Get("whois api"); !ok || balance.Credits != 500 {
+				t.Errorf("Get() got = %+v, %v, want 500 credits", balance, ok)
+			}
+			if _, ok := balances.Get("Email Verification API"); ok {
+				t.Error("Get() of the missing product got ok")
+			}
+		})
+	}
+}
+
+// accountStub is the Account returning the canned balances.
+type accountStub struct {
+	balances Balances
+	err      error
+}
+
+func (a accountStub) Balances(context.Context) (Balances, *Response, error) {
+	return a.balances, nil, a.err
+}
+
+// TestBudgetGuard tests that BudgetGuard refuses purchases when credits are low.
+func TestBudgetGuard(t *testing.T) {
+	balances := Balances{{ProductID: 17, Product: Product{ID: 17, Name: BrandAlertProduct}, Credits: 12}}
+
+	tests := []struct {
+		name       string
+		account    accountStub
+		minCredits int
+		wantErr    string
+		wantIs     error
+	}{
+		{
+			name:       "enough",
+			account:    accountStub{balances: balances},
+			minCredits: 12,
+		},
+		{
+			name:       "low",
+			account:    accountStub{balances: balances},
+			minCredits: 13,
+			wantErr:    "insufficient credits: Brand Alert API has 12 credits, at least 13 required",
+		},
+		{
+			name:       "missing product",
+			account:    accountStub{},
+			minCredits: 1,
+			wantErr:    "Brand Alert API: product not found in the account balance",
+			wantIs:     ErrProductNotFound,
+		},
+		{
+			name:       "balance error",
+			account:    accountStub{err: errors.New("unavailable")},
+			minCredits: 1,
+			wantErr:    "cannot check account balance: unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := NewBudgetGuard(&batchStub{}, tt.account, tt.minCredits)
+
+			_, _, err := guard.Purchase(context.Background(), &SearchTerms{"whois"}, nil)
+			checkErr(t, err, tt.wantErr)
+
+			_, err = guard.RawData(context.Background(), &SearchTerms{"whois"}, nil)
+			if tt.wantErr != "" {
+				checkErr(t, err, tt.wantErr)
+			}
+
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("RawData() error = %v, want %v", err, tt.wantIs)
+			}
+
+			var creditsErr *InsufficientCreditsError
+			if errors.As(err, &creditsErr) && creditsErr.MinCredits != tt.minCredits {
+				t.Errorf("InsufficientCreditsError.MinCredits = %d, want %d", creditsErr.MinCredits, tt.minCredits)
+			}
+
+			// Preview is free and is not guarded.
+			if _, _, err := guard.Preview(context.Background(), &SearchTerms{"whois"}, nil); err == nil ||
+				err.Error() != "not implemented" {
+				t.Errorf("Preview() error = %v, want the client error", err)
+			}
+		})
+	}
+}
+
+// TestBudgetGuardKeyPool tests that BudgetGuard checks the balance of the key
+// used for the purchase without skipping keys of the pool.
+func TestBudgetGuardKeyPool(t *testing.T) {
+	var used []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/account" {
+			used = append(used, "balance:"+req.URL.Query().Get("apiKey"))
+			_, _ = w.Write([]byte(balancesResponse))
+			return
+		}
+
+		var request brandAlertRequest
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			t.Error(err)
+		}
+
+		used = append(used, "purchase:"+request.APIKey)
+		_, _ = w.Write([]byte(`{"domainsCount":0,"domainsList":[]}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountURL, err := url.Parse(server.URL + "/account")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient("", ClientParams{
+		HTTPClient:        server.Client(),
+		BrandAlertBaseURL: apiURL,
+		AccountBaseURL:    accountURL,
+		APIKeyProvider:    NewKeyPool("a", "b"),
+	})
+
+	guard := NewBudgetGuard(client, client.Account, 1)
+
+	for i := 0; i < 2; i++ {
+		_, _, err := guard.Purchase(context.Background(), &SearchTerms{"whois"}, nil)
+		checkErr(t, err, "")
+	}
+
+	if want := []string{"balance:a", "purchase:a", "balance:b", "purchase:b"}; !equalStrings(used, want) {
+		t.Errorf("used keys = %v, want %v", used, want)
+	}
+}
@@ -0,0 +1,352 @@
+package brandalert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoAPIKey is returned when the provider has no API key to use.
+var ErrNoAPIKey = errors.New("no API key available")
+
+// APIKeyProvider provides the API key for every request. Implementations
+// must be safe for concurrent use.
+type APIKeyProvider interface {
+	// APIKey returns the API key for the next request.
+	APIKey(ctx context.Context) (string, error)
+}
+
+// KeyFailover is implemented by the providers able to replace the key which
+// has run out of credits. The client retries the request with the next key
+// until Failover returns false, the context is done or the number of attempts
+// exceeds the number of keys of KeyPool, or 10 for other providers.
+type KeyFailover interface {
+	// Failover marks the key as exhausted and reports whether the request
+	// should be retried with another key.
+	Failover(key string) bool
+}
+
+// KeyPeeker is implemented by the providers rotating the keys, so that the
+// key of the next request can be inspected without advancing the rotation.
+type KeyPeeker interface {
+	// PeekKey returns the key the next APIKey call will return.
+	PeekKey(ctx context.Context) (string, error)
+}
+
+// peekKey returns the key of the next request without advancing the rotation
+// of the provider if it supports it.
+func peekKey(ctx context.Context, keys APIKeyProvider) (string, error) {
+	if p, ok := keys.(KeyPeeker); ok {
+		return p.PeekKey(ctx)
+	}
+
+	return keys.APIKey(ctx)
+}
+
+// defaultKeyAttempts is the maximum number of attempts of the request with
+// the providers which do not report the number of their keys.
+const defaultKeyAttempts = 10
+
+// keyAttempts returns the maximum number of attempts of the request with the
+// keys of the provider failing over the exhausted ones.
+func keyAttempts(keys APIKeyProvider) int {
+	if p, ok := keys.(*KeyPool); ok {
+		return len(p.keys) + 1
+	}
+
+	return defaultKeyAttempts
+}
+
+// creditsExhausted reports whether the API rejected the key because it has
+// no credits left. Other errors, e.g. 403 for an invalid key, are returned
+// to the caller as is.
+func creditsExhausted(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusPaymentRequired
+}
+
+// StaticKey is the APIKeyProvider returning the same key.
+type StaticKey string
+
+// APIKey returns the key.
+func (k StaticKey) APIKey(context.Context) (string, error) {
+	if k == "" {
+		return "", ErrNoAPIKey
+	}
+
+	return string(k), nil
+}
+
+// APIKeyFunc is the adapter to use the function as APIKeyProvider, e.g. to
+// fetch the key from an external secret manager.
+type APIKeyFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f(ctx).
+func (f APIKeyFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// EnvKey returns the provider reading the key from the environment variable
+// on every request.
+func EnvKey(name string) APIKeyProvider {
+	return APIKeyFunc(func(context.Context) (string, error) {
+		key := strings.TrimSpace(os.Getenv(name))
+		if key == "" {
+			return "", fmt.Errorf("environment variable %s is empty: %w", name, ErrNoAPIKey)
+		}
+
+		return key, nil
+	})
+}
+
+// FileKey is the APIKeyProvider reading the key from the file. The file is
+// re-read when its modification time or size changes.
+type FileKey struct {
+	path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+var _ APIKeyProvider = &FileKey{}
+
+// NewFileKey creates the provider reading the key from the file.
+func NewFileKey(path string) *FileKey {
+	return &FileKey{path: path}
+}
+
+// APIKey returns the key from the file.
+func (f *FileKey) APIKey(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read API key: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read API key: %w", err)
+	}
+
+	key := string(bytes.TrimSpace(data))
+	if key == "" {
+		return "", fmt.Errorf("file %s is empty: %w", f.path, ErrNoAPIKey)
+	}
+
+	f.key, f.modTime, f.size = key, info.ModTime(), info.Size()
+
+	return f.key, nil
+}
+
+// DefaultKeyCooldown is the default time the exhausted key of KeyPool is not used.
+const DefaultKeyCooldown = time.Hour
+
+// KeyPool is the APIKeyProvider rotating the keys round-robin. The key which
+// has run out of credits is skipped for Cooldown and the request is retried
+// with the next one.
+type KeyPool struct {
+	keys []string
+
+	mu        sync.Mutex
+	next      int
+	exhausted map[string]time.Time
+
+	// Cooldown is the time the exhausted key is not used. Default: DefaultKeyCooldown.
+	Cooldown time.Duration
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+var (
+	_ APIKeyProvider = &KeyPool{}
+	_ KeyFailover    = &KeyPool{}
+	_ KeyPeeker      = &KeyPool{}
+)
+
+// NewKeyPool creates the pool of the keys. Empty keys are ignored.
+func NewKeyPool(keys ...string) *KeyPool {
+	p := &KeyPool{exhausted: make(map[string]time.Time)}
+
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			p.keys = append(p.keys, key)
+		}
+	}
+
+	return p
+}
+
+// now returns the current time.
+func (p *KeyPool) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// available reports whether the key is not exhausted. It must be called with the lock held.
+func (p *KeyPool) available(key string, now time.Time) bool {
+	until, ok := p.exhausted[key]
+	if !ok {
+		return true
+	}
+
+	if !now.Before(until) {
+		delete(p.exhausted, key)
+		return true
+	}
+
+	return false
+}
+
+// APIKey returns the next available key.
+func (p *KeyPool) APIKey(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i, err := p.find()
+	if err != nil {
+		return "", err
+	}
+
+	p.next = (i + 1) % len(p.keys)
+
+	return p.keys[i], nil
+}
+
+// PeekKey returns the key the next APIKey call will return without
+// advancing the rotation.
+func (p *KeyPool) PeekKey(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i, err := p.find()
+	if err != nil {
+		return "", err
+	}
+
+	return p.keys[i], nil
+}
+
+// find returns the index of the next available key. It must be called with the lock held.
+func (p *KeyPool) find() (int, error) {
+	now := p.now()
+
+	for i := 0; i < len(p.keys); i++ {
+		j := (p.next + i) % len(p.keys)
+
+		if p.available(p.keys[j], now) {
+			return j, nil
+		}
+	}
+
+	if len(p.keys) == 0 {
+		return 0, ErrNoAPIKey
+	}
+
+	return 0, fmt.Errorf("all %d keys are exhausted: %w", len(p.keys), ErrNoAPIKey)
+}
+
+// Failover marks the key as exhausted and reports whether any other key is available.
+func (p *KeyPool) Failover(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cooldown := p.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultKeyCooldown
+	}
+
+	now := p.now()
+	p.exhausted[key] = now.Add(cooldown)
+
+	for _, k := range p.keys {
+		if p.available(k, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CachedKey is the APIKeyProvider caching the key of the slow provider, e.g.
+// an external secret manager, for TTL. The key which has run out of credits
+// is dropped, so the next request fetches it again.
+type CachedKey struct {
+	provider APIKeyProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	key     string
+	expires time.Time
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+var (
+	_ APIKeyProvider = &CachedKey{}
+	_ KeyFailover    = &CachedKey{}
+)
+
+// NewCachedKey creates the provider caching the key of the provider for ttl.
+func NewCachedKey(provider APIKeyProvider, ttl time.Duration) *CachedKey {
+	return &CachedKey{provider: provider, ttl: ttl}
+}
+
+// now returns the current time.
+func (c *CachedKey) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// APIKey returns the cached key fetching it if it has expired.
+func (c *CachedKey) APIKey(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && c.now().Before(c.expires) {
+		return c.key, nil
+	}
+
+	key, err := c.provider.APIKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.key, c.expires = key, c.now().Add(c.ttl)
+
+	return key, nil
+}
+
+// Failover drops the cached key, so the next request fetches it again. The
+// request is retried only if the underlying provider supports failover.
+func (c *CachedKey) Failover(key string) bool {
+	c.mu.Lock()
+	if c.key == key {
+		c.key = ""
+	}
+	c.mu.Unlock()
+
+	if f, ok := c.provider.(KeyFailover); ok {
+		return f.Failover(key)
+	}
+
+	return false
+}
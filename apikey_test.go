@@ -0,0 +1,350 @@
+package brandalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStaticAndEnvKey tests the StaticKey and EnvKey providers.
+func TestStaticAndEnvKey(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := StaticKey("static").APIKey(ctx)
+	checkErr(t, err, "")
+	if key != "static" {
+		t.Errorf("StaticKey got = %q, want %q", key, "static")
+	}
+
+	_, err = StaticKey("").APIKey(ctx)
+	checkErr(t, err, "no API key available")
+
+	t.Setenv("BRAND_ALERT_TEST_KEY", " env\n")
+
+	key, err = EnvKey("BRAND_ALERT_TEST_KEY").APIKey(ctx)
+	checkErr(t, err, "")
+	if key != "env" {
+		t.Errorf("EnvKey got = %q, want %q", key, "env")
+	}
+
+	t.Setenv("BRAND_ALERT_TEST_KEY", "")
+
+	_, err = EnvKey("BRAND_ALERT_TEST_KEY").APIKey(ctx)
+	checkErr(t, err, "environment variable BRAND_ALERT_TEST_KEY is empty: no API key available")
+}
+
+// TestFileKey tests that FileKey re-reads the changed file.
+func TestFileKey(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "key")
+
+	provider := NewFileKey(path)
+
+	if _, err := provider.APIKey(ctx); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("APIKey() error = %v, want os.ErrNotExist", err)
+	}
+
+	for _, want := range []string{"first", "second-key"} {
+		if err := os.WriteFile(path, []byte(want+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := provider.APIKey(ctx)
+		checkErr(t, err, "")
+		if key != want {
+			t.Errorf("APIKey() got = %q, want %q", key, want)
+		}
+	}
+}
+
+// TestKeyPool tests the round-robin rotation and failover of KeyPool.
+func TestKeyPool(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)
+
+	pool := NewKeyPool("a", "", "b", "c")
+	pool.Cooldown = time.Minute
+	pool.Now = func() time.Time { return now }
+
+	next := func(want string, wantErr string) {
+		t.Helper()
+
+		key, err := pool.APIKey(ctx)
+		checkErr(t, err, wantErr)
+		if key != want {
+			t.Errorf("APIKey() got = %q, want %q", key, want)
+		}
+	}
+
+	next("a", "")
+	next("b", "")
+	next("c", "")
+	next("a", "")
+
+	if !pool.Failover("b") {
+		t.Error("Failover(b) = false, want true")
+	}
+	next("c", "")
+	next("a", "")
+
+	if !pool.Failover("c") {
+		t.Error("Failover(c) = false, want true")
+	}
+	if pool.Failover("a") {
+		t.Error("Failover(a) = true, want false")
+	}
+	next("", "all 3 keys are exhausted: no API key available")
+
+	now = now.Add(time.Minute)
+	next("b", "")
+	next("c", "")
+
+	_, err := NewKeyPool().APIKey(ctx)
+	checkErr(t, err, "no API key available")
+}
+
+// TestCachedKey tests that CachedKey caches the key of the secret manager.
+func TestCachedKey(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)
+
+	var (
+		calls  int
+		secret = "secret-1"
+		fail   error
+	)
+
+	// secretManager is the stub of an external secret manager.
+	secretManager := APIKeyFunc(func(context.Context) (string, error) {
+		calls++
+		return secret, fail
+	})
+
+	cached := NewCachedKey(secretManager, time.Minute)
+	cached.Now = func() time.Time { return now }
+
+	steps := []struct {
+		advance   time.Duration
+		secret    string
+		failover  bool
+		fail      error
+		want      string
+		wantCalls int
+		wantErr   string
+	}{
+		{secret: "secret-1", want: "secret-1", wantCalls: 1},
+		{advance: 30 * time.Second, secret: "secret-2", want: "secret-1", wantCalls: 1},
+		{advance: 30 * time.Second, secret: "secret-2", want: "secret-2", wantCalls: 2},
+		{secret: "secret-3", failover: true, want: "secret-3", wantCalls: 3},
+		{advance: time.Minute, fail: errors.New("unavailable"), wantCalls: 4, wantErr: "unavailable"},
+	}
+
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		secret, fail = step.secret, step.fail
+
+		if step.failover && cached.Failover(cached.key) {
+			t.Errorf("step %d: Failover() = true, want false", i)
+		}
+
+		key, err := cached.APIKey(ctx)
+		checkErr(t, err, step.wantErr)
+		if key != step.want || calls != step.wantCalls {
+			t.Errorf("step %d: APIKey() got = %q after %d calls, want %q after %d calls",
+				i, key, calls, step.want, step.wantCalls)
+		}
+	}
+}
+
+// TestKeyPoolFailover tests that the client retries the request with the next key of the pool.
+func TestKeyPoolFailover(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		used []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var request brandAlertRequest
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		used = append(used, request.APIKey)
+		mu.Unlock()
+
+		switch request.APIKey {
+		case "good":
+		case "invalid":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code":403,"messages":"Access restricted. Check the API key."}`))
+			return
+		default:
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"code":402,"messages":"Access restricted. Check credits balance."}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"domainsCount":3}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient("", ClientParams{
+		HTTPClient:        server.Client(),
+		BrandAlertBaseURL: apiURL,
+		APIKeyProvider:    NewKeyPool("empty", "good"),
+	})
+
+	for i := 0; i < 2; i++ {
+		count, _, err := client.Preview(context.Background(), &SearchTerms{"whois"}, nil)
+		checkErr(t, err, "")
+		if count != 3 {
+			t.Errorf("Preview() got = %d, want 3", count)
+		}
+	}
+
+	if want := []string{"empty", "good", "good"}; !equalStrings(used, want) {
+		t.Errorf("used keys = %v, want %v", used, want)
+	}
+
+	client = NewClient("", ClientParams{
+		HTTPClient:        server.Client(),
+		BrandAlertBaseURL: apiURL,
+		APIKeyProvider:    NewKeyPool("empty"),
+	})
+
+	_, _, err = client.Preview(context.Background(), &SearchTerms{"whois"}, nil)
+	checkErr(t, err, "API error: [402] [Access restricted. Check credits balance.]")
+
+	_, _, err = client.Preview(context.Background(), &SearchTerms{"whois"}, nil)
+	checkErr(t, err, "cannot get API key: all 1 keys are exhausted: no API key available")
+
+	// 403 is not the exhaustion, so the key is not failed over.
+	used = nil
+	client = NewClient("", ClientParams{
+		HTTPClient:        server.Client(),
+		BrandAlertBaseURL: apiURL,
+		APIKeyProvider:    NewKeyPool("invalid", "good"),
+	})
+
+	_, _, err = client.Preview(context.Background(), &SearchTerms{"whois"}, nil)
+	checkErr(t, err, "API error: [403] [Access restricted. Check the API key.]")
+
+	var errMsg *ErrorMessage
+	if !errors.As(err, &errMsg) || errMsg.Code != http.StatusForbidden {
+		t.Errorf("Preview() error = %v, want *ErrorMessage with code 403", err)
+	}
+
+	if want := []string{"invalid"}; !equalStrings(used, want) {
+		t.Errorf("used keys = %v, want %v", used, want)
+	}
+}
+
+// endlessFailover is the provider which always reports another key is available.
+type endlessFailover struct {
+	StaticKey
+
+	failover func()
+}
+
+func (e endlessFailover) Failover(string) bool {
+	if e.failover != nil {
+		e.failover()
+	}
+	return true
+}
+
+// TestFailoverLimit tests that the failover stops after the maximum number of
+// attempts or when the context is done.
+func TestFailoverLimit(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"code":402,"messages":"Access restricted. Check credits balance."}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		keys         APIKeyProvider
+		wantRequests int32
+	}{
+		{
+			name:         "pool",
+			ctx:          context.Background(),
+			keys:         NewKeyPool("a", "b"),
+			wantRequests: 2,
+		},
+		{
+			name:         "endless",
+			ctx:          context.Background(),
+			keys:         endlessFailover{StaticKey: "a"},
+			wantRequests: defaultKeyAttempts,
+		},
+		{
+			name:         "canceled",
+			ctx:          ctx,
+			keys:         endlessFailover{StaticKey: "a", failover: cancel},
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+
+			client := NewClient("", ClientParams{
+				HTTPClient:        server.Client(),
+				BrandAlertBaseURL: apiURL,
+				APIKeyProvider:    tt.keys,
+			})
+
+			_, _, err := client.Preview(tt.ctx, &SearchTerms{"whois"}, nil)
+			checkErr(t, err, "API error: [402] [Access restricted. Check credits balance.]")
+
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+// equalStrings reports whether the string slices are equal.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,321 @@
+package brandalert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultParallelism is the default number of queries run at once.
+const defaultParallelism = 4
+
+// Query is the named Brand Alert API query of the batch.
+type Query struct {
+	// Name is the unique query name, e.g. the brand name.
+	Name string
+
+	// Include is the list of terms present in the domain names.
+	Include SearchTerms
+
+	// Exclude is the list of terms absent in the domain names.
+	Exclude SearchTerms
+
+	// Options is the list of the query options.
+	Options []Option
+}
+
+// BatchMode is the failure handling mode of the batch.
+type BatchMode int
+
+const (
+	// BestEffort runs all queries regardless of failures.
+	BestEffort BatchMode = iota
+
+	// FailFast stops the batch on the first failed query. Running queries
+	// are canceled and the remaining ones are skipped.
+	FailFast
+)
+
+// BatchParams is used to run Batch. None of parameters are mandatory.
+type BatchParams struct {
+	// Parallelism is the maximum number of queries run at once. Default: 4.
+	Parallelism int
+
+	// Interval is the minimum time between query starts. Zero means no rate limit.
+	Interval time.Duration
+
+	// Mode is the failure handling mode. Default: BestEffort.
+	Mode BatchMode
+
+	// Progress is called after every finished or skipped query. Calls are
+	// serialized, so the callback doesn't need to be safe for concurrent use.
+	Progress func(BatchProgress)
+}
+
+// BatchProgress is the progress of the batch.
+type BatchProgress struct {
+	// Done is the number of finished and skipped queries.
+	Done int
+
+	// Total is the number of queries.
+	Total int
+
+	// Result is the result of the query that has just finished.
+	Result *QueryResult
+}
+
+// QueryResult is the result of the batch query.
+type QueryResult struct {
+	// Name is the query name.
+	Name string
+
+	// Response is the Brand Alert API response if the query succeeded.
+	Response *BrandAlertResponse
+
+	// Err is the query error.
+	Err error
+
+	// Skipped reports whether the query didn't run because the batch had stopped.
+	Skipped bool
+
+	// Start is the time the query started.
+	Start time.Time
+
+	// Duration is the query duration.
+	Duration time.Duration
+}
+
+// BatchResult is the result of the batch.
+type BatchResult struct {
+	// Results is the list of query results in the order of the queries.
+	Results []QueryResult
+
+	// DomainsCount is the total number of domains in all responses.
+	DomainsCount int
+
+	// UniqueDomains is the number of distinct domain names in all responses.
+	UniqueDomains int
+
+	// Actions is the total number of domains per action.
+	Actions map[Action]int
+
+	// Succeeded is the number of successful queries.
+	Succeeded int
+
+	// Failed is the number of failed queries.
+	Failed int
+
+	// Skipped is the number of skipped queries.
+	Skipped int
+
+	// Duration is the batch duration.
+	Duration time.Duration
+}
+
+// Err returns *BatchError if any query failed.
+func (r *BatchResult) Err() error {
+	var failed []QueryResult
+
+	for _, res := range r.Results {
+		if res.Err != nil && !res.Skipped {
+			failed = append(failed, res)
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &BatchError{Failed: failed}
+}
+
+// BatchError is the error of the failed batch queries.
+type BatchError struct {
+	// Failed is the list of failed query results.
+	Failed []QueryResult
+}
+
+// Error returns error message as a string.
+func (e *BatchError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, res := range e.Failed {
+		msgs = append(msgs, fmt.Sprintf("query %q: %v", res.Name, res.Err))
+	}
+
+	return fmt.Sprintf("%d queries failed: %s", len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the error of the first failed query.
+func (e *BatchError) Unwrap() error {
+	if len(e.Failed) == 0 {
+		return nil
+	}
+
+	return e.Failed[0].Err
+}
+
+// limiter spaces the query starts by the interval.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait waits for the next start slot.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(start.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Batch runs the Purchase queries with bounded parallelism and returns their
+// results. In the BestEffort mode the returned error is nil unless the
+// queries are invalid or ctx is canceled; failed queries are reported by
+// BatchResult.Err. In the FailFast mode the error of the first failed query
+// is returned along with the partial results.
+func Batch(ctx context.Context, client BrandAlert, queries []Query, params BatchParams) (*BatchResult, error) {
+	names := make(map[string]bool, len(queries))
+	for i, q := range queries {
+		if q.Name == "" {
+			return nil, &ArgError{"queries", fmt.Sprintf("query #%d must have a name.", i+1)}
+		}
+		if names[q.Name] {
+			return nil, &ArgError{"queries", fmt.Sprintf("duplicate query name %q.", q.Name)}
+		}
+		names[q.Name] = true
+	}
+
+	parallelism := params.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultParallelism
+	}
+
+	parent := ctx
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+
+	results := make([]QueryResult, len(queries))
+
+	var (
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+
+	finish := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		res := &results[i]
+
+		if res.Err != nil && !res.Skipped && params.Mode == FailFast && firstErr == nil {
+			firstErr = fmt.Errorf("query %q: %w", res.Name, res.Err)
+			cancel()
+		}
+
+		done++
+		if params.Progress != nil {
+			params.Progress(BatchProgress{Done: done, Total: len(queries), Result: res})
+		}
+	}
+
+	lim := &limiter{interval: params.Interval}
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < parallelism && w < len(queries); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				q := queries[i]
+				res := &results[i]
+				res.Name = q.Name
+
+				if err := lim.wait(ctx); err != nil {
+					res.Err, res.Skipped = err, true
+					finish(i)
+					continue
+				}
+
+				res.Start = time.Now()
+				res.Response, _, res.Err = client.Purchase(ctx, q.Include.OrNil(), q.Exclude.OrNil(), q.Options...)
+				res.Duration = time.Since(res.Start)
+
+				// The query canceled by the failed one in the FailFast mode.
+				if errors.Is(res.Err, context.Canceled) && ctx.Err() != nil && parent.Err() == nil {
+					res.Skipped = true
+				}
+
+				finish(i)
+			}
+		}()
+	}
+
+	for i := range queries {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	batch := &BatchResult{Results: results, Actions: make(map[Action]int), Duration: time.Since(start)}
+
+	unique := make(map[string]bool)
+
+	for _, res := range results {
+		switch {
+		case res.Skipped:
+			batch.Skipped++
+		case res.Err != nil:
+			batch.Failed++
+		default:
+			batch.Succeeded++
+		}
+
+		if res.Response == nil {
+			continue
+		}
+
+		batch.DomainsCount += len(res.Response.DomainsList)
+		for _, item := range res.Response.DomainsList {
+			unique[strings.ToLower(item.DomainName)] = true
+			batch.Actions[item.Action]++
+		}
+	}
+
+	batch.UniqueDomains = len(unique)
+
+	if firstErr != nil {
+		return batch, firstErr
+	}
+
+	return batch, parent.Err()
+}
@@ -0,0 +1,299 @@
+package brandalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// batchStub is the BrandAlert returning canned Purchase results by the first include term.
+type batchStub struct {
+	delay     time.Duration
+	responses map[string]*BrandAlertResponse
+	errs      map[string]error
+
+	running, maxRunning int32
+
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (s *batchStub) Purchase(ctx context.Context, include, _ *SearchTerms, _ ...Option) (*BrandAlertResponse, *Response, error) {
+	n := atomic.AddInt32(&s.running, 1)
+	defer atomic.AddInt32(&s.running, -1)
+
+	for {
+		max := atomic.LoadInt32(&s.maxRunning)
+		if n <= max || atomic.CompareAndSwapInt32(&s.maxRunning, max, n) {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.starts = append(s.starts, time.Now())
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-time.After(s.delay):
+	}
+
+	term := (*include)[0]
+	if err := s.errs[term]; err != nil {
+		return nil, nil, err
+	}
+
+	resp := s.responses[term]
+	if resp == nil {
+		resp = &BrandAlertResponse{}
+	}
+
+	return resp, nil, nil
+}
+
+func (s *batchStub) Preview(context.Context, *SearchTerms, *SearchTerms, ...Option) (int, *Response, error) {
+	return 0, nil, errors.New("not implemented")
+}
+
+func (s *batchStub) RawData(context.Context, *SearchTerms, *SearchTerms, ...Option) (*Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+// TestBatch tests the Batch function.
+func TestBatch(t *testing.T) {
+	errUpstream := &ErrorMessage{Code: 500, Message: Messages{"internal error"}}
+
+	responses := map[string]*BrandAlertResponse{
+		"whois": {DomainsCount: 2, DomainsList: []DomainItem{
+			{DomainName: "whois-login.com", Action: Added},
+			{DomainName: "mywhois.net", Action: Dropped},
+		}},
+		"paypal": {DomainsCount: 2, DomainsList: []DomainItem{
+			{DomainName: "paypal-login.com", Action: Added},
+			{DomainName: "Whois-Login.com", Action: Updated},
+		}},
+	}
+
+	queries := func(terms ...string) []Query {
+		var result []Query
+		for _, term := range terms {
+			result = append(result, Query{Name: term, Include: SearchTerms{term}})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		queries   []Query
+		params    BatchParams
+		errs      map[string]error
+		want      BatchResult
+		wantErr   string
+		wantBatch string
+	}{
+		{
+			name:    "best effort",
+			queries: queries("whois", "paypal", "broken", "google"),
+			errs:    map[string]error{"broken": errUpstream},
+			want: BatchResult{
+				DomainsCount:  4,
+				UniqueDomains: 3,
+				Actions:       map[Action]int{Added: 2, Dropped: 1, Updated: 1},
+				Succeeded:     3,
+				Failed:        1,
+			},
+			wantBatch: `1 queries failed: query "broken": API error: [500] [internal error]`,
+		},
+		{
+			name:    "fail fast",
+			queries: queries("broken", "whois", "paypal", "google"),
+			params:  BatchParams{Parallelism: 1, Mode: FailFast},
+			errs:    map[string]error{"broken": errUpstream},
+			want: BatchResult{
+				Actions: map[Action]int{},
+				Failed:  1,
+				Skipped: 3,
+			},
+			wantErr:   `query "broken": API error: [500] [internal error]`,
+			wantBatch: `1 queries failed: query "broken": API error: [500] [internal error]`,
+		},
+		{
+			name:    "no queries",
+			want:    BatchResult{Actions: map[Action]int{}},
+			wantErr: "",
+		},
+		{
+			name:    "empty name",
+			queries: []Query{{Include: SearchTerms{"whois"}}},
+			wantErr: `invalid argument: "queries" query #1 must have a name.`,
+		},
+		{
+			name:    "duplicate name",
+			queries: queries("whois", "whois"),
+			wantErr: `invalid argument: "queries" duplicate query name "whois".`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &batchStub{delay: time.Millisecond, responses: responses, errs: tt.errs}
+
+			got, err := Batch(context.Background(), stub, tt.queries, tt.params)
+			checkErr(t, err, tt.wantErr)
+			if got == nil {
+				return
+			}
+
+			if len(got.Results) != len(tt.queries) {
+				t.Fatalf("Batch() got %d results, want %d", len(got.Results), len(tt.queries))
+			}
+			for i, res := range got.Results {
+				if res.Name != tt.queries[i].Name {
+					t.Errorf("Results[%d].Name = %q, want %q", i, res.Name, tt.queries[i].Name)
+				}
+			}
+
+			if got.DomainsCount != tt.want.DomainsCount || got.UniqueDomains != tt.want.UniqueDomains ||
+				got.Succeeded != tt.want.Succeeded || got.Failed != tt.want.Failed || got.Skipped != tt.want.Skipped {
+				t.Errorf("Batch() got = %+v, want %+v", *got, tt.want)
+			}
+			for action, n := range tt.want.Actions {
+				if got.Actions[action] != n {
+					t.Errorf("Actions[%v] = %d, want %d", action, got.Actions[action], n)
+				}
+			}
+
+			checkErr(t, got.Err(), tt.wantBatch)
+		})
+	}
+}
+
+// TestBatchParallelism tests that Batch bounds the number of running queries.
+func TestBatchParallelism(t *testing.T) {
+	stub := &batchStub{delay: 20 * time.Millisecond}
+
+	var queries []Query
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		queries = append(queries, Query{Name: name, Include: SearchTerms{name}})
+	}
+
+	var (
+		progress []int
+		total    int
+	)
+
+	_, err := Batch(context.Background(), stub, queries, BatchParams{
+		Parallelism: 3,
+		Progress: func(p BatchProgress) {
+			progress = append(progress, p.Done)
+			total = p.Total
+		},
+	})
+	checkErr(t, err, "")
+
+	if stub.maxRunning != 3 {
+		t.Errorf("max running queries = %d, want 3", stub.maxRunning)
+	}
+
+	if len(progress) != len(queries) || total != len(queries) {
+		t.Fatalf("progress got = %v of %d, want %d calls", progress, total, len(queries))
+	}
+	for i, done := range progress {
+		if done != i+1 {
+			t.Errorf("progress[%d].Done = %d, want %d", i, done, i+1)
+		}
+	}
+}
+
+// TestBatchInterval tests that Batch spaces the query starts by the interval.
+func TestBatchInterval(t *testing.T) {
+	const interval = 20 * time.Millisecond
+
+	stub := &batchStub{}
+
+	queries := []Query{
+		{Name: "a", Include: SearchTerms{"a"}},
+		{Name: "b", Include: SearchTerms{"b"}},
+		{Name: "c", Include: SearchTerms{"c"}},
+	}
+
+	_, err := Batch(context.Background(), stub, queries, BatchParams{Parallelism: 3, Interval: interval})
+	checkErr(t, err, "")
+
+	if len(stub.starts) != len(queries) {
+		t.Fatalf("got %d starts, want %d", len(stub.starts), len(queries))
+	}
+
+	// Starts are recorded by the workers, so allow for the scheduling delays.
+	first, last := stub.starts[0], stub.starts[len(stub.starts)-1]
+	if d := last.Sub(first); d < 2*interval-5*time.Millisecond {
+		t.Errorf("queries started within %v, want at least %v", d, 2*interval)
+	}
+}
+
+// TestBatchCanceled tests that Batch skips the queries when ctx is canceled.
+func TestBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := Batch(ctx, &batchStub{}, []Query{{Name: "a", Include: SearchTerms{"a"}}}, BatchParams{})
+	checkErr(t, err, "context canceled")
+
+	if got.Skipped != 1 || !errors.Is(got.Results[0].Err, context.Canceled) {
+		t.Errorf("Batch() got = %+v, want skipped query", got.Results)
+	}
+}
+
+// TestBatchRequestBody tests that queries without exclusions leave them out of the request.
+func TestBatchRequestBody(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []map[string]json.RawMessage
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`{"domainsCount":0,"domainsList":[]}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), BrandAlertBaseURL: apiURL})
+
+	queries := []Query{
+		{Name: "a", Include: SearchTerms{"whois"}},
+		{Name: "b", Include: SearchTerms{"whois"}, Exclude: SearchTerms{}},
+	}
+
+	_, err = Batch(context.Background(), client, queries, BatchParams{})
+	checkErr(t, err, "")
+
+	if len(bodies) != len(queries) {
+		t.Fatalf("got %d requests, want %d", len(bodies), len(queries))
+	}
+	for _, body := range bodies {
+		if v, ok := body["excludeSearchTerms"]; ok {
+			t.Errorf("request body has excludeSearchTerms = %s, want none", v)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package blocklist exports Brand Alert API domains as DNS blocklists:
+// BIND Response Policy Zones, hosts files, dnsmasq and Unbound configs.
+package blocklist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// defaultAddress is the default sinkhole address.
+const defaultAddress = "0.0.0.0"
+
+// Domains returns the sorted list of unique lower case domain names without the trailing dot.
+func Domains(items []brandalert.DomainItem) []string {
+	set := make(map[string]struct{}, len(items))
+
+	for _, item := range items {
+		name := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(item.DomainName)), ".")
+		if name != "" {
+			set[name] = struct{}{}
+		}
+	}
+
+	domains := make([]string, 0, len(set))
+	for name := range set {
+		domains = append(domains, name)
+	}
+
+	sort.Strings(domains)
+
+	return domains
+}
+
+// HostsParams is used to write hosts files. None of parameters are mandatory.
+type HostsParams struct {
+	// Address is the sinkhole IPv4 address. Default: 0.0.0.0.
+	Address string
+
+	// AddressV6 is the sinkhole IPv6 address, e.g. "::". If it's empty then
+	// IPv6 entries are not written.
+	AddressV6 string
+}
+
+// WriteHosts writes the domains in the hosts file format. Note that hosts
+// files can't block subdomains.
+func WriteHosts(w io.Writer, items []brandalert.DomainItem, params HostsParams) error {
+	address := params.Address
+	if address == "" {
+		address = defaultAddress
+	}
+
+	return writeLines(w, items, func(bw *bufio.Writer, domain string) {
+		fmt.Fprintf(bw, "%s %s\n", address, domain)
+		if params.AddressV6 != "" {
+			fmt.Fprintf(bw, "%s %s\n", params.AddressV6, domain)
+		}
+	})
+}
+
+// DnsmasqParams is used to write dnsmasq configs. None of parameters are mandatory.
+type DnsmasqParams struct {
+	// Address is the sinkhole address. Default: 0.0.0.0.
+	Address string
+}
+
+// WriteDnsmasq writes the domains as dnsmasq "address=" lines. The lines
+// block the domains and all their subdomains.
+func WriteDnsmasq(w io.Writer, items []brandalert.DomainItem, params DnsmasqParams) error {
+	address := params.Address
+	if address == "" {
+		address = defaultAddress
+	}
+
+	return writeLines(w, items, func(bw *bufio.Writer, domain string) {
+		fmt.Fprintf(bw, "address=/%s/%s\n", domain, address)
+	})
+}
+
+// UnboundParams is used to write Unbound configs. None of parameters are mandatory.
+type UnboundParams struct {
+	// ZoneType is the local-zone type. Default: always_nxdomain.
+	// If Address is set, "redirect" is used.
+	ZoneType string
+
+	// Address is the sinkhole address. If it's set, the domains and all their
+	// subdomains resolve to it.
+	Address string
+
+	// NoServerClause disables the "server:" line, e.g. for included files.
+	NoServerClause bool
+}
+
+// WriteUnbound writes the domains as Unbound "local-zone" entries. The
+// entries block the domains and all their subdomains.
+func WriteUnbound(w io.Writer, items []brandalert.DomainItem, params UnboundParams) error {
+	zoneType := params.ZoneType
+	switch {
+	case params.Address != "":
+		zoneType = "redirect"
+	case zoneType == "":
+		zoneType = "always_nxdomain"
+	}
+
+	rrType := "A"
+	if strings.Contains(params.Address, ":") {
+		rrType = "AAAA"
+	}
+
+	header := "server:\n"
+	if params.NoServerClause {
+		header = ""
+	}
+
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	return writeLines(w, items, func(bw *bufio.Writer, domain string) {
+		fmt.Fprintf(bw, "    local-zone: \"%s.\" %s\n", domain, zoneType)
+		if params.Address != "" {
+			fmt.Fprintf(bw, "    local-data: \"%s. %s %s\"\n", domain, rrType, params.Address)
+		}
+	})
+}
+
+// writeLines writes the lines for every domain.
+func writeLines(w io.Writer, items []brandalert.DomainItem, line func(bw *bufio.Writer, domain string)) error {
+	bw := bufio.NewWriter(w)
+
+	for _, domain := range Domains(items) {
+		line(bw, domain)
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,88 @@
+package blocklist
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+var testItems = []brandalert.DomainItem{
+	{DomainName: "whoisdodster.com", Action: brandalert.Added},
+	{DomainName: "BatchWhois.com.", Action: brandalert.Discovered},
+	{DomainName: "whoisdodster.com", Action: brandalert.Updated},
+	{DomainName: " ", Action: brandalert.Added},
+}
+
+// TestDomains tests the Domains function.
+func TestDomains(t *testing.T) {
+	want := []string{"batchwhois.com", "whoisdodster.com"}
+
+	if got := Domains(testItems); !reflect.DeepEqual(got, want) {
+		t.Errorf("Domains() got = %v, want %v", got, want)
+	}
+}
+
+// TestWriters tests the hosts, dnsmasq and Unbound writers.
+func TestWriters(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(b *bytes.Buffer) error
+		want  string
+	}{
+		{
+			name: "hosts",
+			write: func(b *bytes.Buffer) error {
+				return WriteHosts(b, testItems, HostsParams{})
+			},
+			want: "0.0.0.0 batchwhois.com\n0.0.0.0 whoisdodster.com\n",
+		},
+		{
+			name: "hosts ipv6",
+			write: func(b *bytes.Buffer) error {
+				return WriteHosts(b, testItems[:1], HostsParams{Address: "127.0.0.1", AddressV6: "::1"})
+			},
+			want: "127.0.0.1 whoisdodster.com\n::1 whoisdodster.com\n",
+		},
+		{
+			name: "dnsmasq",
+			write: func(b *bytes.Buffer) error {
+				return WriteDnsmasq(b, testItems, DnsmasqParams{})
+			},
+			want: "address=/batchwhois.com/0.0.0.0\naddress=/whoisdodster.com/0.0.0.0\n",
+		},
+		{
+			name: "unbound",
+			write: func(b *bytes.Buffer) error {
+				return WriteUnbound(b, testItems, UnboundParams{})
+			},
+			want: `server:
+    local-zone: "batchwhois.com." always_nxdomain
+    local-zone: "whoisdodster.com." always_nxdomain
+`,
+		},
+		{
+			name: "unbound redirect",
+			write: func(b *bytes.Buffer) error {
+				return WriteUnbound(b, testItems[:1], UnboundParams{Address: "::", NoServerClause: true})
+			},
+			want: `    local-zone: "whoisdodster.com." redirect
+    local-data: "whoisdodster.com. AAAA ::"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			if err := tt.write(&b); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("got = %v, want %v", b.String(), tt.want)
+			}
+		})
+	}
+}
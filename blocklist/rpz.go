@@ -0,0 +1,214 @@
+package blocklist
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/internal/atomicfile"
+)
+
+// List of RPZ policies.
+const (
+	// PolicyNXDOMAIN makes the domains non-existent.
+	PolicyNXDOMAIN = "."
+
+	// PolicyNODATA makes the domains have no records.
+	PolicyNODATA = "*."
+
+	// PolicyDrop drops the queries.
+	PolicyDrop = "rpz-drop."
+)
+
+// RPZParams is used to write Response Policy Zones. None of parameters are
+// mandatory and leaving this struct empty works just fine for most cases.
+type RPZParams struct {
+	// Origin is the zone name. If it's set, the $ORIGIN directive is written.
+	Origin string
+
+	// TTL is the default TTL in seconds. Default: 300.
+	TTL uint32
+
+	// PrimaryNS is the SOA primary name server. Default: localhost.
+	PrimaryNS string
+
+	// Hostmaster is the SOA responsible mailbox. Default: hostmaster.localhost.
+	Hostmaster string
+
+	// Refresh, Retry, Expire and Minimum are the SOA timers in seconds.
+	// Defaults: 3600, 600, 604800, 300.
+	Refresh, Retry, Expire, Minimum uint32
+
+	// Policy is the CNAME target: PolicyNXDOMAIN (default), PolicyNODATA,
+	// PolicyDrop or a walled garden host name.
+	Policy string
+
+	// NoSubdomains disables blocking of the subdomains.
+	NoSubdomains bool
+}
+
+// withDefaults returns the parameters with the default values set.
+func (p RPZParams) withDefaults() RPZParams {
+	setDefault := func(v *uint32, def uint32) {
+		if *v == 0 {
+			*v = def
+		}
+	}
+
+	setDefault(&p.TTL, 300)
+	setDefault(&p.Refresh, 3600)
+	setDefault(&p.Retry, 600)
+	setDefault(&p.Expire, 604800)
+	setDefault(&p.Minimum, 300)
+
+	if p.PrimaryNS == "" {
+		p.PrimaryNS = "localhost."
+	}
+	if p.Hostmaster == "" {
+		p.Hostmaster = "hostmaster.localhost."
+	}
+	if p.Policy == "" {
+		p.Policy = PolicyNXDOMAIN
+	}
+
+	return p
+}
+
+// WriteRPZ writes the domains as the Response Policy Zone with the given SOA serial.
+func WriteRPZ(w io.Writer, items []brandalert.DomainItem, serial uint32, params RPZParams) error {
+	return writeRPZ(w, Domains(items), serial, params)
+}
+
+// writeRPZ writes the normalized domains as the Response Policy Zone.
+func writeRPZ(w io.Writer, domains []string, serial uint32, params RPZParams) error {
+	p := params.withDefaults()
+
+	bw := bufio.NewWriter(w)
+
+	if p.Origin != "" {
+		fmt.Fprintf(bw, "$ORIGIN %s.\n", strings.TrimSuffix(p.Origin, "."))
+	}
+
+	fmt.Fprintf(bw, "$TTL %d\n", p.TTL)
+	fmt.Fprintf(bw, "@ IN SOA %s %s %d %d %d %d %d\n",
+		p.PrimaryNS, p.Hostmaster, serial, p.Refresh, p.Retry, p.Expire, p.Minimum)
+	fmt.Fprintf(bw, "  IN NS %s\n", p.PrimaryNS)
+	fmt.Fprintf(bw, "; %d domain(s)\n", len(domains))
+
+	for _, domain := range domains {
+		fmt.Fprintf(bw, "%s CNAME %s\n", domain, p.Policy)
+		if !p.NoSubdomains {
+			fmt.Fprintf(bw, "*.%s CNAME %s\n", domain, p.Policy)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// NextSerial returns the date-based SOA serial (YYYYMMDDnn) following prev.
+func NextSerial(prev uint32, now time.Time) uint32 {
+	y, m, d := now.UTC().Date()
+
+	serial := uint32(y*1000000 + int(m)*10000 + d*100)
+	if prev >= serial {
+		return prev + 1
+	}
+
+	return serial
+}
+
+// State is the state of the zone kept between updates.
+type State struct {
+	// Serial is the SOA serial.
+	Serial uint32 `json:"serial"`
+
+	// Digest is the SHA-256 digest of the domain set.
+	Digest string `json:"digest"`
+}
+
+// Next returns the state for the domains. Serial is bumped only if the set
+// of domains differs from the one of the current state.
+func (s State) Next(items []brandalert.DomainItem, now time.Time) (next State, changed bool) {
+	digest := Digest(items)
+	if digest == s.Digest && s.Serial != 0 {
+		return s, false
+	}
+
+	return State{
+		Serial: NextSerial(s.Serial, now),
+		Digest: digest,
+	}, true
+}
+
+// Digest returns the SHA-256 digest of the set of domains. The order and
+// duplicates of the items don't matter.
+func Digest(items []brandalert.DomainItem) string {
+	h := sha256.New()
+
+	for _, domain := range Domains(items) {
+		h.Write([]byte(domain))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// serialRe matches the SOA serial written by WriteRPZ.
+var serialRe = regexp.MustCompile(`(?m)^@ IN SOA \S+ \S+ (\d+) `)
+
+// UpdateRPZFile writes the Response Policy Zone file only if the zone content
+// has changed. The SOA serial of the existing file is bumped then. It returns
+// true if the file was written. The file is replaced atomically.
+func UpdateRPZFile(path string, items []brandalert.DomainItem, params RPZParams) (bool, error) {
+	return updateRPZFile(path, items, params, time.Now())
+}
+
+// updateRPZFile is UpdateRPZFile with the specified current time.
+func updateRPZFile(path string, items []brandalert.DomainItem, params RPZParams, now time.Time) (bool, error) {
+	domains := Domains(items)
+
+	var prevSerial uint32
+
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if m := serialRe.FindSubmatch(old); m != nil {
+			serial, err := strconv.ParseUint(string(m[1]), 10, 32)
+			if err != nil {
+				return false, fmt.Errorf("cannot parse serial: %w", err)
+			}
+			prevSerial = uint32(serial)
+
+			var b bytes.Buffer
+			if err := writeRPZ(&b, domains, prevSerial, params); err != nil {
+				return false, err
+			}
+
+			if bytes.Equal(b.Bytes(), old) {
+				return false, nil
+			}
+		}
+	case !os.IsNotExist(err):
+		return false, fmt.Errorf("cannot read zone: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := writeRPZ(&b, domains, NextSerial(prevSerial, now), params); err != nil {
+		return false, err
+	}
+
+	if err := atomicfile.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return false, fmt.Errorf("cannot write zone: %w", err)
+	}
+
+	return true, nil
+}
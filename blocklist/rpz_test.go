@@ -0,0 +1,140 @@
+package blocklist
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// TestWriteRPZ tests the WriteRPZ function.
+func TestWriteRPZ(t *testing.T) {
+	tests := []struct {
+		name   string
+		params RPZParams
+		want   string
+	}{
+		{
+			name: "default",
+			want: `$TTL 300
+@ IN SOA localhost. hostmaster.localhost. 2022103001 3600 600 604800 300
+  IN NS localhost.
+; 2 domain(s)
+batchwhois.com CNAME .
+*.batchwhois.com CNAME .
+whoisdodster.com CNAME .
+*.whoisdodster.com CNAME .
+`,
+		},
+		{
+			name: "custom",
+			params: RPZParams{
+				Origin:       "rpz.example.com.",
+				TTL:          60,
+				PrimaryNS:    "ns1.example.com.",
+				Hostmaster:   "admin.example.com.",
+				Policy:       "walled-garden.example.com.",
+				NoSubdomains: true,
+			},
+			want: `$ORIGIN rpz.example.com.
+$TTL 60
+@ IN SOA ns1.example.com. admin.example.com. 2022103001 3600 600 604800 300
+  IN NS ns1.example.com.
+; 2 domain(s)
+batchwhois.com CNAME walled-garden.example.com.
+whoisdodster.com CNAME walled-garden.example.com.
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			if err := WriteRPZ(&b, testItems, 2022103001, tt.params); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("WriteRPZ() got = %v, want %v", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestNextSerial tests the NextSerial function.
+func TestNextSerial(t *testing.T) {
+	now := time.Date(2022, 10, 30, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		prev, want uint32
+	}{
+		{0, 2022103000},
+		{2022102905, 2022103000},
+		{2022103000, 2022103001},
+		{2022110100, 2022110101},
+	}
+	for _, tt := range tests {
+		if got := NextSerial(tt.prev, now); got != tt.want {
+			t.Errorf("NextSerial(%d) got = %v, want %v", tt.prev, got, tt.want)
+		}
+	}
+}
+
+// TestStateNext tests the State.Next function.
+func TestStateNext(t *testing.T) {
+	now := time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)
+
+	state, changed := State{}.Next(testItems, now)
+	if !changed || state.Serial != 2022103000 {
+		t.Fatalf("Next() got = %v, %v", state, changed)
+	}
+
+	reordered := []brandalert.DomainItem{testItems[1], testItems[0]}
+	if next, changed := state.Next(reordered, now); changed || next != state {
+		t.Errorf("Next() got = %v, %v, want unchanged", next, changed)
+	}
+
+	if next, changed := state.Next(testItems[:1], now); !changed || next.Serial != 2022103001 {
+		t.Errorf("Next() got = %v, %v, want changed", next, changed)
+	}
+}
+
+// TestUpdateRPZFile tests the UpdateRPZFile function.
+func TestUpdateRPZFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brand-alert.rpz")
+
+	day1 := time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	steps := []struct {
+		items   []brandalert.DomainItem
+		now     time.Time
+		changed bool
+		serial  string
+	}{
+		{testItems, day1, true, "2022103000"},
+		{testItems, day1, false, "2022103000"},
+		{testItems[:1], day1, true, "2022103001"},
+		{testItems[:1], day2, false, "2022103001"},
+		{testItems, day2, true, "2022103100"},
+	}
+	for i, step := range steps {
+		changed, err := updateRPZFile(path, step.items, RPZParams{}, step.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := serialRe.FindSubmatch(content)
+		if changed != step.changed || m == nil || string(m[1]) != step.serial {
+			t.Errorf("step %d: updateRPZFile() got = %v, serial %s, want %v, %s", i, changed, m, step.changed, step.serial)
+		}
+	}
+}
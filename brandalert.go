@@ -27,6 +27,13 @@ type Response struct {
 
 	// Body is the byte slice representation of http.Response Body.
 	Body []byte
+
+	// CompressedSize is the number of body bytes received. It equals
+	// UncompressedSize if the body was not compressed.
+	CompressedSize int64
+
+	// UncompressedSize is the size of the decompressed body.
+	UncompressedSize int64
 }
 
 // brandAlertServiceOp is the type implementing the BrandAlert interface.
@@ -68,6 +75,12 @@ func validateSearchTerms(includeSearchTerms *SearchTerms, excludeSearchTerms *Se
 	return nil
 }
 
+// ValidateSearchTerms validates the terms of search the same way Purchase,
+// Preview and RawData do. It returns *ArgError if the terms are invalid.
+func ValidateSearchTerms(includeSearchTerms *SearchTerms, excludeSearchTerms *SearchTerms) error {
+	return validateSearchTerms(includeSearchTerms, excludeSearchTerms)
+}
+
 // validateOptions validates options.
 func validateOptions(opts ...Option) error {
 	for _, opt := range opts {
@@ -90,7 +103,7 @@ func (service brandAlertServiceOp) request(
 	}
 
 	var request = &brandAlertRequest{
-		service.client.apiKey,
+		"",
 		includeSearchTerms,
 		excludeSearchTerms,
 		"",
@@ -112,6 +125,43 @@ func (service brandAlertServiceOp) request(
 		opt(request)
 	}
 
+	timeout := service.client.previewTimeout
+	if purchase {
+		timeout = service.client.purchaseTimeout
+	}
+
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = withDefaultTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	keys := service.client.keys
+	attempts := keyAttempts(keys)
+
+	for attempt := 1; ; attempt++ {
+		key, err := keys.APIKey(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get API key: %w", err)
+		}
+
+		request.APIKey = key
+
+		resp, err := service.send(ctx, request)
+		if err == nil && creditsExhausted(resp.Response) {
+			f, ok := keys.(KeyFailover)
+			if ok && f.Failover(key) && attempt < attempts && ctx.Err() == nil {
+				continue
+			}
+		}
+
+		return resp, err
+	}
+}
+
+// send sends the API request.
+func (service brandAlertServiceOp) send(ctx context.Context, request *brandAlertRequest) (*Response, error) {
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
@@ -124,18 +174,14 @@ func (service brandAlertServiceOp) request(
 
 	var b bytes.Buffer
 
-	resp, err := service.client.Do(ctx, req, &b)
-	if err != nil {
-		return &Response{
-			Response: resp,
-			Body:     b.Bytes(),
-		}, err
-	}
+	resp, size, err := service.client.do(ctx, req, &b)
 
 	return &Response{
-		Response: resp,
-		Body:     b.Bytes(),
-	}, nil
+		Response:         resp,
+		Body:             b.Bytes(),
+		CompressedSize:   size.compressed,
+		UncompressedSize: size.uncompressed,
+	}, err
 }
 
 // parse parses raw Brand Alert API response.
@@ -150,6 +196,40 @@ func parse(raw []byte) (*apiResponse, error) {
 	return &response, nil
 }
 
+// checkActions returns UnknownActionError if the raw response contains an unknown action.
+func checkActions(raw []byte, items []DomainItem) error {
+	unknown := false
+	for _, item := range items {
+		if item.Action == Unknown {
+			unknown = true
+			break
+		}
+	}
+
+	if !unknown {
+		return nil
+	}
+
+	var response struct {
+		DomainsList []struct {
+			DomainName string `json:"domainName"`
+			Action     string `json:"action"`
+		} `json:"domainsList"`
+	}
+
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return fmt.Errorf("cannot parse response: %w", err)
+	}
+
+	for _, item := range response.DomainsList {
+		if _, err := ParseAction(item.Action); err != nil {
+			return &UnknownActionError{DomainName: item.DomainName, Value: item.Action}
+		}
+	}
+
+	return nil
+}
+
 // Purchase returns parsed Brand Alert API response.
 func (service brandAlertServiceOp) Purchase(
 	ctx context.Context,
@@ -177,6 +257,12 @@ func (service brandAlertServiceOp) Purchase(
 		}
 	}
 
+	if service.client.strictActions {
+		if err = checkActions(resp.Body, brandAlertResp.DomainsList); err != nil {
+			return nil, resp, err
+		}
+	}
+
 	return &brandAlertResp.BrandAlertResponse, resp, nil
 }
 
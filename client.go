@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
@@ -27,6 +28,42 @@ type ClientParams struct {
 
 	// BrandAlertBaseURL is the endpoint for 'Brand Alert API' service
 	BrandAlertBaseURL *url.URL
+
+	// AccountBaseURL is the endpoint for the account balance service
+	AccountBaseURL *url.URL
+
+	// APIKeyProvider provides the API key for every request. If it's nil then
+	// the apiKey argument of NewClient is used.
+	APIKeyProvider APIKeyProvider
+
+	// StrictActions makes Purchase return UnknownActionError if the response
+	// contains an unknown action. By default, unknown actions are decoded to Unknown.
+	StrictActions bool
+
+	// ConnectTimeout is the time limit to dial and to complete the TLS handshake.
+	// It's applied to the copy of HTTPClient if its transport is nil or
+	// *http.Transport, keeping the custom dialer of the transport.
+	ConnectTimeout time.Duration
+
+	// HeaderTimeout is the time limit to receive the response headers since
+	// the request is sent, including connecting.
+	HeaderTimeout time.Duration
+
+	// BodyTimeout is the time limit to read the whole response body after
+	// the headers are received.
+	BodyTimeout time.Duration
+
+	// PreviewTimeout is the deadline applied to Preview requests if the
+	// context has none. Default: DefaultPreviewTimeout. Negative means no deadline.
+	PreviewTimeout time.Duration
+
+	// PurchaseTimeout is the deadline applied to Purchase and RawData requests
+	// if the context has none. Default: DefaultPurchaseTimeout. Negative means no deadline.
+	PurchaseTimeout time.Duration
+
+	// MaxBodySize is the limit of the decompressed response body size guarding
+	// against decompression bombs. Default: DefaultMaxBodySize. Negative means no limit.
+	MaxBodySize int64
 }
 
 // NewBasicClient creates Client with recommended parameters.
@@ -51,26 +88,80 @@ func NewClient(apiKey string, params ClientParams) *Client {
 		httpClient = params.HTTPClient
 	}
 
+	if params.ConnectTimeout > 0 {
+		httpClient = withConnectTimeout(httpClient, params.ConnectTimeout)
+	}
+
+	var keys APIKeyProvider = StaticKey(apiKey)
+	if params.APIKeyProvider != nil {
+		keys = params.APIKeyProvider
+	}
+
 	client := &Client{
-		client:    httpClient,
-		userAgent: userAgent,
-		apiKey:    apiKey,
+		client:          httpClient,
+		userAgent:       userAgent,
+		keys:            keys,
+		strictActions:   params.StrictActions,
+		connectTimeout:  params.ConnectTimeout,
+		headerTimeout:   params.HeaderTimeout,
+		bodyTimeout:     params.BodyTimeout,
+		previewTimeout:  defaultTimeout(params.PreviewTimeout, DefaultPreviewTimeout),
+		purchaseTimeout: defaultTimeout(params.PurchaseTimeout, DefaultPurchaseTimeout),
+		maxBodySize:     params.MaxBodySize,
+	}
+
+	if client.maxBodySize == 0 {
+		client.maxBodySize = DefaultMaxBodySize
+	}
+
+	accountBaseURL := params.AccountBaseURL
+	if accountBaseURL == nil {
+		accountBaseURL, err = url.Parse(defaultAccountURL)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	client.BrandAlert = &brandAlertServiceOp{client: client, baseURL: apiBaseURL}
+	client.Account = &accountServiceOp{client: client, baseURL: accountBaseURL}
 
 	return client
 }
 
+// defaultTimeout returns the default if the timeout is zero and zero if it's negative.
+func defaultTimeout(timeout, def time.Duration) time.Duration {
+	switch {
+	case timeout == 0:
+		return def
+	case timeout < 0:
+		return 0
+	}
+
+	return timeout
+}
+
 // Client is the client for Brand Alert API services.
 type Client struct {
 	client *http.Client
 
 	userAgent string
-	apiKey    string
+	keys      APIKeyProvider
+
+	strictActions bool
+
+	connectTimeout  time.Duration
+	headerTimeout   time.Duration
+	bodyTimeout     time.Duration
+	previewTimeout  time.Duration
+	purchaseTimeout time.Duration
+
+	maxBodySize int64
 
 	// BrandAlert is an interface for Brand Alert API
 	BrandAlert
+
+	// Account is an interface for the account balance
+	Account Account
 }
 
 // NewRequest creates a basic API request.
@@ -87,31 +178,71 @@ func (c *Client) NewRequest(method string, u *url.URL, body io.Reader) (*http.Re
 	req.Header.Add("Content-Type", mediaType)
 	req.Header.Add("Accept", mediaType)
 	req.Header.Add("User-Agent", c.userAgent)
+	req.Header.Add("Accept-Encoding", acceptEncoding)
 
 	return req, nil
 }
 
-// Do sends the API request and returns the API response.
-func (c *Client) Do(ctx context.Context, req *http.Request, v io.Writer) (response *http.Response, err error) {
+// Do sends the API request and returns the API response. The gzip or deflate
+// compressed body is decompressed. Timeouts are returned as *TimeoutError.
+func (c *Client) Do(ctx context.Context, req *http.Request, v io.Writer) (*http.Response, error) {
+	resp, _, err := c.do(ctx, req, v)
+	return resp, err
+}
+
+// bodySize is the size of the response body.
+type bodySize struct {
+	compressed, uncompressed int64
+}
+
+// do sends the API request and returns the API response and its body size.
+func (c *Client) do(ctx context.Context, req *http.Request, v io.Writer) (response *http.Response, size bodySize, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	timer := newPhaseTimer(cancel, c.connectTimeout)
+	defer timer.stop()
+
+	timer.start(TimeoutHeader, c.headerTimeout)
+
 	req = req.WithContext(ctx)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("cannot execute request: %w", err)
+		return nil, size, fmt.Errorf("cannot execute request: %w", timer.wrap(ctx, err))
 	}
 
+	timer.start(TimeoutBody, c.bodyTimeout)
+
 	defer func() {
 		if rerr := resp.Body.Close(); err == nil && rerr != nil {
 			err = fmt.Errorf("cannot close response: %w", rerr)
 		}
 	}()
 
-	_, err = io.Copy(v, resp.Body)
+	wire := &countingReader{r: resp.Body}
+
+	encoding := resp.Header.Get("Content-Encoding")
+
+	body, err := decodeBody(wire, encoding)
 	if err != nil {
-		return resp, fmt.Errorf("cannot read response: %w", err)
+		return resp, size, fmt.Errorf("cannot read response: %w", timer.wrap(ctx, err))
 	}
 
-	return resp, err
+	if body != io.Reader(wire) {
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+
+	size.uncompressed, err = copyBody(v, body, c.maxBodySize)
+	size.compressed = wire.n
+	if err != nil {
+		return resp, size, fmt.Errorf("cannot read response: %w", timer.wrap(ctx, err))
+	}
+
+	return resp, size, nil
 }
 
 // ErrorResponse is returned when the response status code is not 2xx.
@@ -2,6 +2,7 @@ package brandalert
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -598,3 +599,69 @@ func TestBrandAlertRawData(t *testing.T) {
 		})
 	}
 }
+
+// TestBrandAlertPurchaseStrictActions tests the StrictActions parameter.
+func TestBrandAlertPurchaseStrictActions(t *testing.T) {
+	const resp = `{"domainsCount":2,"domainsList":[
+{"domainName":"batchwhois.com","date":"2022-10-30","action":"added"},
+{"domainName":"whoisdodster.com","date":"2022-10-30","action":"transferred"}]}`
+
+	server := dummyServer(resp, "", "")
+	defer server.Close()
+
+	for _, strict := range []bool{false, true} {
+		t.Run(strconv.FormatBool(strict), func(t *testing.T) {
+			api := newAPI(server, pathBrandAlertResponseOK)
+			api.strictActions = strict
+
+			got, _, err := api.Purchase(context.Background(), &SearchTerms{"whois"}, nil)
+			if strict {
+				var actionErr *UnknownActionError
+				if !errors.As(err, &actionErr) || actionErr.DomainName != "whoisdodster.com" || actionErr.Value != "transferred" {
+					t.Errorf("BrandAlert.Purchase() error = %v, want UnknownActionError", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("BrandAlert.Purchase() error = %v", err)
+			}
+
+			if got.DomainsList[1].Action != Unknown {
+				t.Errorf("BrandAlert.Purchase() got = %v, want %v", got.DomainsList[1].Action, Unknown)
+			}
+		})
+	}
+}
+
+// TestValidateSearchTerms tests the ValidateSearchTerms function.
+func TestValidateSearchTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		include *SearchTerms
+		exclude *SearchTerms
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			include: &SearchTerms{"whois"},
+			exclude: &SearchTerms{"api"},
+		},
+		{
+			name:    "no include",
+			include: &SearchTerms{},
+			wantErr: `invalid argument: "includeSearchTerms" must have between 1 and 4 items.`,
+		},
+		{
+			name:    "too many exclude",
+			include: &SearchTerms{"whois"},
+			exclude: &SearchTerms{"a", "b", "c", "d", "e"},
+			wantErr: `invalid argument: "excludeSearchTerms" must have between 0 and 4 items.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErr(t, ValidateSearchTerms(tt.include, tt.exclude), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Command brandalertd runs the brand searches of the multi-brand configuration
+// on their schedules and sends the found domains to the notification targets.
+//
+// The API key is read from the BRAND_ALERT_API_KEY environment variable.
+// SIGHUP reloads the configuration, SIGINT and SIGTERM stop the daemon after
+// the running search finishes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/daemon"
+	"github.com/whois-api-llc/brand-alert-go/store"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "brands.yaml", "multi-brand configuration file")
+		statePath  = flag.String("state", "brandalertd.state.json", "state file")
+		storePath  = flag.String("store", "", "bbolt database of the state used instead of the state file")
+		listen     = flag.String("listen", ":8080", "address of the health endpoints, empty to disable")
+		timeout    = flag.Duration("shutdown-timeout", 30*time.Second, "time to wait for running searches on shutdown")
+
+		connectTimeout  = flag.Duration("connect-timeout", 10*time.Second, "time limit to connect to the API")
+		headerTimeout   = flag.Duration("header-timeout", time.Minute, "time limit to receive the API response headers")
+		bodyTimeout     = flag.Duration("body-timeout", time.Minute, "time limit to read the API response body")
+		purchaseTimeout = flag.Duration("purchase-timeout", brandalert.DefaultPurchaseTimeout, "deadline of the purchase requests")
+	)
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "brandalertd: ", log.LstdFlags)
+
+	apiKey := os.Getenv("BRAND_ALERT_API_KEY")
+	if apiKey == "" {
+		logger.Fatal("BRAND_ALERT_API_KEY is not set")
+	}
+
+	var stateStore store.Store
+
+	if *storePath != "" {
+		s, err := store.OpenBolt(*storePath)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer s.Close()
+
+		stateStore = s
+	}
+
+	d, err := daemon.New(daemon.Params{
+		ConfigPath: *configPath,
+		StatePath:  *statePath,
+		Store:      stateStore,
+		Client: brandalert.NewClient(apiKey, brandalert.ClientParams{
+			ConnectTimeout:  *connectTimeout,
+			HeaderTimeout:   *headerTimeout,
+			BodyTimeout:     *bodyTimeout,
+			PurchaseTimeout: *purchaseTimeout,
+		}),
+		Logger:          logger,
+		ShutdownTimeout: *timeout,
+	})
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		for range hup {
+			if err := d.Reload(); err != nil {
+				logger.Print(err)
+			}
+		}
+	}()
+
+	var server *http.Server
+
+	if *listen != "" {
+		server = &http.Server{Addr: *listen, Handler: d.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal(err)
+			}
+		}()
+	}
+
+	if err := d.Run(ctx); err != nil {
+		logger.Print(err)
+	}
+
+	if server != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Print(err)
+		}
+	}
+
+	logger.Print("stopped")
+}
@@ -0,0 +1,117 @@
+package brandalert
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// acceptEncoding is the Accept-Encoding header of the API requests.
+	acceptEncoding = "gzip, deflate"
+
+	// DefaultMaxBodySize is the default limit of the decompressed response body size.
+	DefaultMaxBodySize = 256 << 20
+)
+
+// ErrBodyTooLarge is returned when the decompressed response body exceeds
+// ClientParams.MaxBodySize.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// countingReader counts the bytes read from the reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from the underlying reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decodeBody returns the reader decoding the body with the content encoding.
+func decodeBody(body io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		br := bufio.NewReader(body)
+		if empty, err := isEmpty(br); empty || err != nil {
+			return br, err
+		}
+
+		r, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress response: %w", err)
+		}
+		return r, nil
+	case "deflate":
+		br := bufio.NewReader(body)
+		if empty, err := isEmpty(br); empty || err != nil {
+			return br, err
+		}
+
+		// Some servers send raw deflate data instead of zlib.
+		header, err := br.Peek(2)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("cannot decompress response: %w", err)
+		}
+
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			r, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decompress response: %w", err)
+			}
+			return r, nil
+		}
+
+		return flate.NewReader(br), nil
+	}
+
+	return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+}
+
+// isEmpty reports whether the body has no data, e.g. the body of 204 No
+// Content, so there is nothing to decompress.
+func isEmpty(br *bufio.Reader) (bool, error) {
+	_, err := br.Peek(1)
+	if errors.Is(err, io.EOF) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot decompress response: %w", err)
+	}
+
+	return false, nil
+}
+
+// copyBody copies the body to the writer failing with ErrBodyTooLarge if it
+// exceeds the limit. At most limit bytes are written. Non-positive limit means
+// no limit.
+func copyBody(w io.Writer, body io.Reader, limit int64) (int64, error) {
+	if limit <= 0 {
+		return io.Copy(w, body)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(body, limit))
+	if err != nil {
+		return n, err
+	}
+
+	if n == limit {
+		// Check if anything is left without writing it.
+		var b [1]byte
+		if m, _ := io.ReadFull(body, b[:]); m > 0 {
+			return n + int64(m), fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, limit)
+		}
+	}
+
+	return n, nil
+}
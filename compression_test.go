@@ -0,0 +1,203 @@
+package brandalert
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// compress returns the data compressed with the content encoding.
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	var (
+		b bytes.Buffer
+		w io.WriteCloser
+	)
+
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&b)
+	case "deflate":
+		w = zlib.NewWriter(&b)
+	case "raw-deflate":
+		var err error
+		if w, err = flate.NewWriter(&b, flate.DefaultCompression); err != nil {
+			t.Fatal(err)
+		}
+	default:
+		return data
+	}
+
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+// TestCompression tests decompression of the API responses.
+func TestCompression(t *testing.T) {
+	body := []byte(`{"domainsCount":2,"domainsList":[` +
+		strings.Repeat(`{"domainName":"whois-login.com","date":"2022-10-30","action":"added"},`, 100) +
+		`{"domainName":"whoisfinder.net","date":"2022-10-30","action":"added"}]}`)
+
+	tests := []struct {
+		name           string
+		encoding       string
+		header         string
+		maxBodySize    int64
+		wantCompressed bool
+		wantErr        string
+	}{
+		{
+			name: "identity",
+		},
+		{
+			name:           "gzip",
+			encoding:       "gzip",
+			header:         "gzip",
+			wantCompressed: true,
+		},
+		{
+			name:           "zlib deflate",
+			encoding:       "deflate",
+			header:         "deflate",
+			wantCompressed: true,
+		},
+		{
+			name:           "raw deflate",
+			encoding:       "raw-deflate",
+			header:         "deflate",
+			wantCompressed: true,
+		},
+		{
+			name:        "decompression bomb",
+			encoding:    "gzip",
+			header:      "gzip",
+			maxBodySize: 1024,
+			wantErr:     "cannot read response: response body too large: limit is 1024 bytes",
+		},
+		{
+			name:    "unsupported",
+			header:  "br",
+			wantErr: `cannot read response: unsupported content encoding "br"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if got := req.Header.Get("Accept-Encoding"); got != acceptEncoding {
+					t.Errorf("Accept-Encoding = %q, want %q", got, acceptEncoding)
+				}
+
+				if tt.header != "" {
+					w.Header().Set("Content-Encoding", tt.header)
+				}
+				_, _ = w.Write(compress(t, tt.encoding, body))
+			}))
+			defer server.Close()
+
+			apiURL, err := url.Parse(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := NewClient(apiKey, ClientParams{
+				HTTPClient:        server.Client(),
+				BrandAlertBaseURL: apiURL,
+				MaxBodySize:       tt.maxBodySize,
+			})
+
+			resp, err := client.RawData(context.Background(), &SearchTerms{"whois"}, nil)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if !bytes.Equal(resp.Body, body) {
+				t.Errorf("RawData() body = %.100s, want %.100s", resp.Body, body)
+			}
+			if resp.UncompressedSize != int64(len(body)) {
+				t.Errorf("UncompressedSize = %d, want %d", resp.UncompressedSize, len(body))
+			}
+
+			compressed := resp.CompressedSize < resp.UncompressedSize
+			if compressed != tt.wantCompressed {
+				t.Errorf("CompressedSize = %d of %d, want compressed %v",
+					resp.CompressedSize, resp.UncompressedSize, tt.wantCompressed)
+			}
+			if resp.Uncompressed != tt.wantCompressed || (tt.wantCompressed && resp.Header.Get("Content-Encoding") != "") {
+				t.Errorf("Uncompressed = %v, Content-Encoding = %q", resp.Uncompressed, resp.Header.Get("Content-Encoding"))
+			}
+		})
+	}
+}
+
+// TestDecodeBody tests decoding of the empty and truncated bodies.
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		body     string
+		wantErr  string
+	}{
+		{name: "empty identity"},
+		{name: "empty deflate", encoding: "deflate"},
+		{name: "truncated deflate", encoding: "deflate", body: "x", wantErr: "unexpected EOF"},
+		{name: "empty gzip", encoding: "gzip"},
+		{name: "truncated gzip", encoding: "gzip", body: "\x1f", wantErr: "cannot decompress response: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := decodeBody(strings.NewReader(tt.body), tt.encoding)
+			if err == nil {
+				var data []byte
+				data, err = io.ReadAll(r)
+				if err == nil && len(data) != 0 {
+					t.Errorf("decodeBody() got = %q, want empty", data)
+				}
+			}
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestCopyBody tests the copyBody function.
+func TestCopyBody(t *testing.T) {
+	var b bytes.Buffer
+
+	n, err := copyBody(&b, strings.NewReader("12345"), 5)
+	checkErr(t, err, "")
+	if n != 5 || b.String() != "12345" {
+		t.Errorf("copyBody() = %d, %q", n, b.String())
+	}
+
+	b.Reset()
+
+	_, err = copyBody(&b, strings.NewReader("123456"), 5)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("copyBody() error = %v, want ErrBodyTooLarge", err)
+	}
+	if b.String() != "12345" {
+		t.Errorf("copyBody() wrote %q, want at most the limit", b.String())
+	}
+
+	n, err = copyBody(io.Discard, strings.NewReader("123456"), -1)
+	checkErr(t, err, "")
+	if n != 6 {
+		t.Errorf("copyBody() = %d, want 6", n)
+	}
+}
@@ -0,0 +1,584 @@
+// Package config describes monitored brands declaratively: search terms,
+// options, lookback, suppression rules and notification targets. The
+// configuration is loaded from YAML or JSON files and validated with errors
+// pointing at the offending line.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/schedule"
+	"github.com/whois-api-llc/brand-alert-go/suppress"
+)
+
+// Format is the format of the configuration file.
+type Format string
+
+// List of supported formats.
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+var _ = []Format{
+	JSON,
+	YAML,
+}
+
+// Config is the configuration of monitored brands.
+type Config struct {
+	// Defaults is the set of settings used by brands not overriding them.
+	Defaults Defaults `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+
+	// Notifications maps the target name to the notification target.
+	Notifications map[string]Target `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+
+	// Brands is the list of monitored brands.
+	Brands []Brand `json:"brands" yaml:"brands"`
+
+	// file is the path the configuration was loaded from.
+	file string
+
+	// lines maps field paths to lines of the configuration file.
+	lines lines
+}
+
+// Defaults is the set of default brand settings.
+type Defaults struct {
+	// WithTypos enriches the search terms with their typos.
+	WithTypos *bool `json:"withTypos,omitempty" yaml:"withTypos,omitempty"`
+
+	// Punycode encodes domain names in responses to Punycode.
+	Punycode *bool `json:"punycode,omitempty" yaml:"punycode,omitempty"`
+
+	// Lookback is the search period, e.g. "36h" or "7d".
+	Lookback string `json:"lookback,omitempty" yaml:"lookback,omitempty"`
+
+	// Notify is the list of notification target names.
+	Notify []string `json:"notify,omitempty" yaml:"notify,omitempty"`
+
+	// Schedule is the cron-like expression of the search times, see the schedule package.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+
+	// Jitter is the maximum random delay of the scheduled searches, e.g. "5m".
+	Jitter string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+}
+
+// Brand is the monitored brand.
+type Brand struct {
+	// Name is the unique brand name.
+	Name string `json:"name" yaml:"name"`
+
+	// Include is the list of including search terms.
+	Include brandalert.SearchTerms `json:"include" yaml:"include"`
+
+	// Exclude is the list of excluding search terms.
+	Exclude brandalert.SearchTerms `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	// WithTypos enriches the search terms with their typos. Default: Defaults.WithTypos.
+	WithTypos *bool `json:"withTypos,omitempty" yaml:"withTypos,omitempty"`
+
+	// Punycode encodes domain names in responses to Punycode. Default: Defaults.Punycode.
+	Punycode *bool `json:"punycode,omitempty" yaml:"punycode,omitempty"`
+
+	// Lookback is the search period, e.g. "36h" or "7d". Default: Defaults.Lookback.
+	// If both are empty, Brand Alert API default is used.
+	Lookback string `json:"lookback,omitempty" yaml:"lookback,omitempty"`
+
+	// Suppress is the list of suppression rules.
+	Suppress []Rule `json:"suppress,omitempty" yaml:"suppress,omitempty"`
+
+	// SuppressFile is the suppression rules file, relative to the configuration file.
+	SuppressFile string `json:"suppressFile,omitempty" yaml:"suppressFile,omitempty"`
+
+	// Notify is the list of notification target names. Default: Defaults.Notify.
+	Notify []string `json:"notify,omitempty" yaml:"notify,omitempty"`
+
+	// Schedule is the cron-like expression of the search times. Default: Defaults.Schedule.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+
+	// Jitter is the maximum random delay of the scheduled searches. Default: Defaults.Jitter.
+	Jitter string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+}
+
+// Rule is the suppression rule.
+type Rule struct {
+	// Type is the type of the rule: exact | suffix | regex | registrable.
+	Type suppress.Type `json:"type" yaml:"type"`
+
+	// Value is the domain name, suffix or regular expression depending on Type.
+	Value string `json:"value" yaml:"value"`
+
+	// Reason explains why the matching domains are suppressed.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// Expires is the expiration date or RFC 3339 time.
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+}
+
+// Target is the notification target.
+type Target struct {
+	// Type is the target type: webhook | email | syslog.
+	Type string `json:"type" yaml:"type"`
+
+	// URL is the webhook URL.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Template is the webhook template: json | slack | teams.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Secret is the webhook HMAC-SHA256 signing key.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+
+	// Headers is the list of additional webhook request headers.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Address is the host:port of the SMTP or syslog server.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+
+	// Username and Password are the SMTP credentials.
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+
+	// StartTLS is the SMTP STARTTLS policy: opportunistic | required | disabled.
+	StartTLS string `json:"startTLS,omitempty" yaml:"startTLS,omitempty"`
+
+	// From is the email sender.
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+
+	// To is the list of email recipients.
+	To []string `json:"to,omitempty" yaml:"to,omitempty"`
+
+	// Recipients maps the brand name to its email recipients.
+	Recipients map[string][]string `json:"recipients,omitempty" yaml:"recipients,omitempty"`
+
+	// Subject is the email subject prefix.
+	Subject string `json:"subject,omitempty" yaml:"subject,omitempty"`
+
+	// Network is the syslog transport: udp | tcp | tls.
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+
+	// Format is the syslog message format: cef | leef.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// envRe matches ${NAME} references to environment variables.
+var envRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Parse parses and validates the configuration in the specified format.
+// ${NAME} references in the string fields of the notification targets are
+// replaced with environment variables after decoding. Other fields, e.g.
+// lookback or withTypos, are not expanded and can not use references.
+func Parse(data []byte, format Format) (*Config, error) {
+	return parse(data, format, "")
+}
+
+// Load loads and validates the configuration file. The format is detected
+// by the file extension: .json, .yaml or .yml.
+func Load(path string) (*Config, error) {
+	var format Format
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = JSON
+	case ".yaml", ".yml":
+		format = YAML
+	default:
+		return nil, fmt.Errorf(`cannot detect format of "%s"`, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+
+	return parse(data, format, path)
+}
+
+// parse parses the configuration loaded from the file.
+func parse(data []byte, format Format, file string) (*Config, error) {
+	cfg := &Config{file: file}
+
+	switch format {
+	case JSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			offset := dec.InputOffset()
+
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntaxErr):
+				offset = syntaxErr.Offset
+			case errors.As(err, &typeErr):
+				offset = typeErr.Offset
+			case strings.HasPrefix(err.Error(), "json: unknown field "):
+				// The decoder has already skipped the value, so look for the key.
+				key := strings.TrimPrefix(err.Error(), "json: unknown field ")
+				if i := bytes.LastIndex(data[:offset], []byte(key)); i >= 0 {
+					offset = int64(i)
+				}
+			}
+
+			return nil, &Error{File: file, Line: lineOf(data, offset), Message: err.Error()}
+		}
+		cfg.lines = jsonLines(data)
+	case YAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil {
+			return nil, yamlError(err, file)
+		}
+		cfg.lines = yamlLines(data)
+	default:
+		return nil, fmt.Errorf(`cannot parse config: unknown format "%s"`, format)
+	}
+
+	if err := cfg.expandEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// yamlLineRe matches the line prefix of YAML error messages.
+var yamlLineRe = regexp.MustCompile(`(?s)^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError returns the YAML decoding error with line numbers. Every field
+// error of *yaml.TypeError becomes a separate *Error.
+func yamlError(err error, file string) error {
+	lineError := func(msg string) *Error {
+		m := yamlLineRe.FindStringSubmatch(msg)
+		if m == nil {
+			return &Error{File: file, Message: msg}
+		}
+
+		line, _ := strconv.Atoi(m[1])
+		return &Error{File: file, Line: line, Message: m[2]}
+	}
+
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		errs := make(Errors, 0, len(typeErr.Errors))
+		for _, msg := range typeErr.Errors {
+			errs = append(errs, lineError(msg))
+		}
+		return errs
+	}
+
+	return lineError(err.Error())
+}
+
+// expandEnv replaces ${NAME} references in the string fields of the
+// notification targets with environment variables. It runs after decoding, so
+// the values are never parsed as part of the document.
+func (c *Config) expandEnv() error {
+	var errs Errors
+
+	expand := func(path string, s *string) {
+		*s = envRe.ReplaceAllStringFunc(*s, func(ref string) string {
+			name := envRe.FindStringSubmatch(ref)[1]
+
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				errs = append(errs, &Error{
+					File:    c.file,
+					Line:    c.lines.lookup(path),
+					Path:    path,
+					Message: "environment variable " + name + " is not set",
+				})
+			}
+
+			return value
+		})
+	}
+
+	names := make([]string, 0, len(c.Notifications))
+	for name := range c.Notifications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := c.Notifications[name]
+		path := join("notifications", name)
+
+		expand(join(path, "url"), &target.URL)
+		expand(join(path, "secret"), &target.Secret)
+		expand(join(path, "address"), &target.Address)
+		expand(join(path, "username"), &target.Username)
+		expand(join(path, "password"), &target.Password)
+		expand(join(path, "from"), &target.From)
+
+		for key, value := range target.Headers {
+			expand(join(join(path, "headers"), key), &value)
+			target.Headers[key] = value
+		}
+
+		for i := range target.To {
+			expand(index(join(path, "to"), i), &target.To[i])
+		}
+
+		c.Notifications[name] = target
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ParseLookback parses the lookback period: a number of days like "7d" or
+// a duration like "36h". An empty string is zero.
+func ParseLookback(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	var d time.Duration
+
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf(`invalid lookback "%s"`, s)
+		}
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf(`invalid lookback "%s"`, s)
+		}
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf(`lookback "%s" must be positive`, s)
+	}
+
+	return d, nil
+}
+
+// Validate checks the configuration and returns Errors if it's invalid.
+func (c *Config) Validate() error {
+	var errs Errors
+
+	add := func(path, msg string) {
+		errs = append(errs, &Error{File: c.file, Line: c.lines.lookup(path), Path: path, Message: msg})
+	}
+
+	for name, target := range c.Notifications {
+		if _, err := newSink(name, target, nil); err != nil {
+			add(join("notifications", name), err.Error())
+		}
+	}
+
+	if _, err := ParseLookback(c.Defaults.Lookback); err != nil {
+		add("defaults.lookback", err.Error())
+	}
+
+	validateSchedule(c.Defaults.Schedule, c.Defaults.Jitter, "defaults", add)
+
+	for i, name := range c.Defaults.Notify {
+		if _, ok := c.Notifications[name]; !ok {
+			add(index("defaults.notify", i), `unknown notification target "`+name+`"`)
+		}
+	}
+
+	if len(c.Brands) == 0 {
+		add("brands", "at least one brand is required")
+	}
+
+	names := make(map[string]bool, len(c.Brands))
+
+	for i, brand := range c.Brands {
+		path := index("brands", i)
+
+		switch {
+		case brand.Name == "":
+			add(join(path, "name"), "name can not be empty")
+		case names[brand.Name]:
+			add(join(path, "name"), `duplicate brand "`+brand.Name+`"`)
+		}
+		names[brand.Name] = true
+
+		if err := brandalert.ValidateSearchTerms(&brand.Include, &brand.Exclude); err != nil {
+			field := "include"
+
+			var argErr *brandalert.ArgError
+			if errors.As(err, &argErr) {
+				if argErr.Name == "excludeSearchTerms" {
+					field = "exclude"
+				}
+				err = errors.New(argErr.Message)
+			}
+
+			add(join(path, field), err.Error())
+		}
+
+		if _, err := ParseLookback(brand.Lookback); err != nil {
+			add(join(path, "lookback"), err.Error())
+		}
+
+		validateSchedule(brand.Schedule, brand.Jitter, path, add)
+
+		if _, err := brand.rules(); err != nil {
+			p := join(path, "suppress")
+
+			var ruleErr *suppress.RuleError
+			if errors.As(err, &ruleErr) {
+				p = index(p, ruleErr.Index)
+			}
+
+			add(p, err.Error())
+		}
+
+		for j, name := range brand.Notify {
+			if _, ok := c.Notifications[name]; !ok {
+				add(index(join(path, "notify"), j), `unknown notification target "`+name+`"`)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sortErrors(errs)
+
+	return errs
+}
+
+// validateSchedule validates the schedule and jitter settings at the path.
+func validateSchedule(spec, jitter, path string, add func(path, msg string)) {
+	if spec != "" {
+		if _, err := schedule.Parse(spec); err != nil {
+			add(join(path, "schedule"), err.Error())
+		}
+	}
+
+	if jitter != "" {
+		if d, err := time.ParseDuration(jitter); err != nil || d < 0 {
+			add(join(path, "jitter"), `invalid jitter "`+jitter+`"`)
+		}
+	}
+}
+
+// Resolved returns the brands with the default settings applied.
+func (c *Config) Resolved() []Brand {
+	brands := make([]Brand, 0, len(c.Brands))
+
+	for _, b := range c.Brands {
+		if b.WithTypos == nil {
+			b.WithTypos = c.Defaults.WithTypos
+		}
+		if b.Punycode == nil {
+			b.Punycode = c.Defaults.Punycode
+		}
+		if b.Lookback == "" {
+			b.Lookback = c.Defaults.Lookback
+		}
+		if b.Notify == nil {
+			b.Notify = c.Defaults.Notify
+		}
+		if b.Schedule == "" {
+			b.Schedule = c.Defaults.Schedule
+		}
+		if b.Jitter == "" {
+			b.Jitter = c.Defaults.Jitter
+		}
+		brands = append(brands, b)
+	}
+
+	return brands
+}
+
+// SuppressList creates the suppression list of the brand from the inline
+// rules and the rules file. Relative rules file paths are resolved against
+// the configuration file directory.
+func (c *Config) SuppressList(b Brand) (*suppress.List, error) {
+	rules, err := b.rules()
+	if err != nil {
+		return nil, err
+	}
+
+	if b.SuppressFile != "" {
+		path := b.SuppressFile
+		if !filepath.IsAbs(path) && c.file != "" {
+			path = filepath.Join(filepath.Dir(c.file), path)
+		}
+
+		list, err := suppress.Load(path)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, list.Rules()...)
+	}
+
+	return suppress.New(rules...)
+}
+
+// Options returns the Brand Alert API options of the brand.
+func (b Brand) Options(now time.Time) ([]brandalert.Option, error) {
+	var opts []brandalert.Option
+
+	lookback, err := ParseLookback(b.Lookback)
+	if err != nil {
+		return nil, err
+	}
+	if lookback > 0 {
+		opts = append(opts, brandalert.OptionSinceDate(now.Add(-lookback)))
+	}
+
+	if b.WithTypos != nil {
+		opts = append(opts, brandalert.OptionWithTypos(*b.WithTypos))
+	}
+	if b.Punycode != nil {
+		opts = append(opts, brandalert.OptionPunycode(*b.Punycode))
+	}
+
+	return opts, nil
+}
+
+// rules returns the inline suppression rules of the brand.
+func (b Brand) rules() ([]suppress.Rule, error) {
+	rules := make([]suppress.Rule, 0, len(b.Suppress))
+
+	for i, r := range b.Suppress {
+		rule := suppress.Rule{Type: r.Type, Value: r.Value, Reason: r.Reason}
+
+		if r.Expires != "" {
+			expires, err := suppress.ParseExpires(r.Expires)
+			if err != nil {
+				return nil, &suppress.RuleError{Index: i, Message: err.Error()}
+			}
+			rule.Expires = expires
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if _, err := suppress.New(rules...); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
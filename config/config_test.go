@@ -0,0 +1,305 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+const configYAML = `defaults:
+  lookback: 7d
+  withTypos: true
+  notify: [slack]
+
+notifications:
+  slack:
+    type: webhook
+    url: https://hooks.example.com/${BRAND_ALERT_TEST_HOOK}
+    template: slack
+  legal:
+    type: email
+    address: smtp.example.com:587
+    from: alerts@example.com
+    to: [legal@example.com]
+
+brands:
+  - name: WhoisXML
+    include: [whois, xml]
+    exclude: [api]
+    suppress:
+      - type: exact
+        value: whoisxmlapi.com
+        reason: official
+    notify: [slack, legal]
+  - name: Acme
+    include: [acme]
+    withTypos: false
+    lookback: 36h
+`
+
+const configJSON = `{
+  "defaults": {"lookback": "7d", "withTypos": true, "notify": ["slack"]},
+  "notifications": {
+    "slack": {"type": "webhook", "url": "https://hooks.example.com/${BRAND_ALERT_TEST_HOOK}", "template": "slack"},
+    "legal": {"type": "email", "address": "smtp.example.com:587", "from": "alerts@example.com", "to": ["legal@example.com"]}
+  },
+  "brands": [
+    {
+      "name": "WhoisXML",
+      "include": ["whois", "xml"],
+      "exclude": ["api"],
+      "suppress": [{"type": "exact", "value": "whoisxmlapi.com", "reason": "official"}],
+      "notify": ["slack", "legal"]
+    },
+    {"name": "Acme", "include": ["acme"], "withTypos": false, "lookback": "36h"}
+  ]
+}`
+
+// TestLoad tests loading equivalent YAML and JSON configurations.
+func TestLoad(t *testing.T) {
+	t.Setenv("BRAND_ALERT_TEST_HOOK", "T000/B000")
+
+	dir := t.TempDir()
+
+	var configs []*Config
+
+	for name, content := range map[string]string{"brands.yaml": configYAML, "brands.json": configJSON} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", name, err)
+		}
+		configs = append(configs, cfg)
+	}
+
+	for _, cfg := range configs {
+		if got := cfg.Notifications["slack"].URL; got != "https://hooks.example.com/T000/B000" {
+			t.Errorf("Load() got url = %v", got)
+		}
+
+		brands := cfg.Resolved()
+		if len(brands) != 2 || brands[0].Lookback != "7d" || !*brands[0].WithTypos || *brands[1].WithTypos ||
+			!reflect.DeepEqual(brands[1].Notify, []string{"slack"}) || !reflect.DeepEqual(brands[0].Exclude, brandalert.SearchTerms{"api"}) {
+			t.Errorf("Resolved() got = %+v", brands)
+		}
+
+		list, err := cfg.SuppressList(brands[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rule, ok := list.Match("WhoisXMLAPI.com"); !ok || rule.Reason != "official" {
+			t.Errorf("SuppressList() got = %v, %v", rule, ok)
+		}
+	}
+
+	if !reflect.DeepEqual(configs[0].Brands, configs[1].Brands) || !reflect.DeepEqual(configs[0].Notifications, configs[1].Notifications) {
+		t.Errorf("Load() YAML and JSON configurations differ")
+	}
+}
+
+// TestValidate tests validation errors with line numbers.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		content string
+		wantErr string
+	}{
+		{
+			name:   "yaml",
+			format: YAML,
+			content: `notifications:
+  chat:
+    type: irc
+brands:
+  - name: WhoisXML
+    include: [a, b, c, d, e]
+    lookback: -1d
+  - name: WhoisXML
+    include:
+      - whois
+    exclude: [a, b, c, d, e]
+    suppress:
+      - type: exact
+        value: whois.com
+      - type: prefix
+        value: whois
+    notify: [slack]
+`,
+			wantErr: `line 2: notifications.chat: unknown target type "irc"
+line 6: brands[0].include: must have between 1 and 4 items.
+line 7: brands[0].lookback: lookback "-1d" must be positive
+line 8: brands[1].name: duplicate brand "WhoisXML"
+line 11: brands[1].exclude: must have between 0 and 4 items.
+line 15: brands[1].suppress[1]: invalid rule #2: unknown type "prefix"
+line 17: brands[1].notify[0]: unknown notification target "slack"`,
+		},
+		{
+			name:   "json",
+			format: JSON,
+			content: `{
+  "brands": [
+    {
+      "name": "",
+      "include": [],
+      "lookback": "week"
+    }
+  ]
+}`,
+			wantErr: `line 4: brands[0].name: name can not be empty
+line 5: brands[0].include: must have between 1 and 4 items.
+line 6: brands[0].lookback: invalid lookback "week"`,
+		},
+		{
+			name:    "schedule",
+			format:  YAML,
+			content: "defaults:\n  jitter: soon\nbrands:\n  - name: WhoisXML\n    include: [whois]\n    schedule: \"0 25 * * *\"\n",
+			wantErr: `line 2: defaults.jitter: invalid jitter "soon"
+line 6: brands[0].schedule: invalid schedule "0 25 * * *": invalid hour "25"`,
+		},
+		{
+			name:    "no brands",
+			format:  YAML,
+			content: "defaults:\n  lookback: 1d\n",
+			wantErr: "brands: at least one brand is required",
+		},
+		{
+			name:    "yaml unknown field",
+			format:  YAML,
+			content: "brands:\n  - name: WhoisXML\n    includes: [whois]\n",
+			wantErr: "line 3: field includes not found in type config.Brand",
+		},
+		{
+			name:    "yaml type",
+			format:  YAML,
+			content: "brands:\n  - name: WhoisXML\n    include: [whois]\n    withTypos: maybe\n    notify: slack\n",
+			wantErr: "line 4: cannot unmarshal !!str `maybe` into bool\n" +
+				"line 5: cannot unmarshal !!str `slack` into []string",
+		},
+		{
+			name:    "yaml syntax",
+			format:  YAML,
+			content: "brands:\n  - name: WhoisXML\n    include: [whois]\n\tnotify: [slack]\n",
+			wantErr: "line 4: found character that cannot start any token",
+		},
+		{
+			name:    "json syntax",
+			format:  JSON,
+			content: "{\n  \"brands\": [\n    {\"name\": \"WhoisXML\",}\n  ]\n}",
+			wantErr: "line 3: invalid character '}' looking for beginning of object key string",
+		},
+		{
+			name:    "json type",
+			format:  JSON,
+			content: "{\n  \"brands\": [\n    {\"name\": 1}\n  ]\n}",
+			wantErr: "line 3: json: cannot unmarshal number into Go struct field ",
+		},
+		{
+			name:    "json unknown field",
+			format:  JSON,
+			content: "{\n  \"brands\": [],\n  \"brand\": {}\n}",
+			wantErr: `line 3: json: unknown field "brand"`,
+		},
+		{
+			name:    "environment",
+			format:  YAML,
+			content: "notifications:\n  slack:\n    type: webhook\n    url: ${BRAND_ALERT_TEST_MISSING}\n",
+			wantErr: "line 4: notifications.slack.url: environment variable BRAND_ALERT_TEST_MISSING is not set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content), tt.format)
+			if err != nil && strings.HasSuffix(tt.wantErr, " ") && strings.HasPrefix(err.Error(), tt.wantErr) {
+				// The rest of the message depends on the Go version.
+				return
+			}
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestExpandEnv tests that environment variables are expanded verbatim after
+// decoding only in the notification targets and references in YAML comments
+// are ignored.
+func TestExpandEnv(t *testing.T) {
+	const secret = "a\"b: c # d\ne"
+
+	t.Setenv("BRAND_ALERT_TEST_SECRET", secret)
+
+	content := `# url: ${BRAND_ALERT_TEST_UNSET}
+notifications:
+  hook:
+    type: webhook
+    url: https://hooks.example.com/
+    secret: ${BRAND_ALERT_TEST_SECRET} # ${BRAND_ALERT_TEST_UNSET}
+    headers:
+      X-Token: "Bearer ${BRAND_ALERT_TEST_SECRET}"
+brands:
+  - name: WhoisXML
+    include: [whois]
+`
+
+	cfg, err := Parse([]byte(content), YAML)
+	checkErr(t, err, "")
+	if err != nil {
+		return
+	}
+
+	target := cfg.Notifications["hook"]
+	if target.Secret != secret {
+		t.Errorf("Secret got = %q, want %q", target.Secret, secret)
+	}
+	if got := target.Headers["X-Token"]; got != "Bearer "+secret {
+		t.Errorf("X-Token got = %q, want %q", got, "Bearer "+secret)
+	}
+
+	// Only the string fields of the notification targets are expanded.
+	t.Setenv("BRAND_ALERT_TEST_LOOKBACK", "7d")
+	t.Setenv("BRAND_ALERT_TEST_TYPOS", "true")
+
+	_, err = Parse([]byte("brands:\n  - name: WhoisXML\n    include: [whois]\n    lookback: ${BRAND_ALERT_TEST_LOOKBACK}\n"), YAML)
+	checkErr(t, err, `line 4: brands[0].lookback: invalid lookback "${BRAND_ALERT_TEST_LOOKBACK}"`)
+
+	_, err = Parse([]byte("brands:\n  - name: WhoisXML\n    include: [whois]\n    withTypos: ${BRAND_ALERT_TEST_TYPOS}\n"), YAML)
+	checkErr(t, err, "line 4: cannot unmarshal !!str `${BRAND...` into bool")
+}
+
+// TestParseLookback tests the ParseLookback function.
+func TestParseLookback(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr string
+	}{
+		{value: "", want: 0},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "36h", want: 36 * time.Hour},
+		{value: "0d", wantErr: `lookback "0d" must be positive`},
+		{value: "1w", wantErr: `invalid lookback "1w"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLookback(tt.value)
+			checkErr(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("ParseLookback() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error is the configuration error located in the file.
+type Error struct {
+	// File is the configuration file path. It's empty if the configuration was not loaded from a file.
+	File string
+
+	// Line is the one-based line number. Zero means unknown.
+	Line int
+
+	// Path is the path to the invalid field, e.g. "brands[0].include".
+	Path string
+
+	// Message is the error message.
+	Message string
+}
+
+// Error returns error message as a string.
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	switch {
+	case e.File != "" && e.Line > 0:
+		fmt.Fprintf(&b, "%s:%d: ", e.File, e.Line)
+	case e.File != "":
+		b.WriteString(e.File + ": ")
+	case e.Line > 0:
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+
+	if e.Path != "" {
+		b.WriteString(e.Path + ": ")
+	}
+
+	b.WriteString(e.Message)
+
+	return b.String()
+}
+
+// Errors is the list of configuration errors.
+type Errors []*Error
+
+// Error returns error messages separated by new lines.
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// lines maps field paths to line numbers.
+type lines map[string]int
+
+// lookup returns the line of the path or of its closest parent.
+func (l lines) lookup(path string) int {
+	for {
+		if line, ok := l[path]; ok {
+			return line
+		}
+
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return 0
+		}
+		path = path[:i]
+	}
+}
+
+// join returns the path of the key in the parent path.
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// index returns the path of the element in the parent path.
+func index(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// yamlLines returns the lines of all fields of the YAML document.
+func yamlLines(data []byte) lines {
+	res := make(lines)
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return res
+	}
+
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		if _, ok := res[path]; !ok {
+			res[path] = node.Line
+		}
+
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, n := range node.Content {
+				walk(n, path)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				p := join(path, key.Value)
+				res[p] = key.Line
+				walk(value, p)
+			}
+		case yaml.SequenceNode:
+			for i, n := range node.Content {
+				walk(n, index(path, i))
+			}
+		case yaml.AliasNode:
+			if node.Alias != nil {
+				walk(node.Alias, path)
+			}
+		}
+	}
+
+	walk(&node, "")
+
+	return res
+}
+
+// jsonLines returns the lines of all fields of the valid JSON document.
+func jsonLines(data []byte) lines {
+	res := make(lines)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	line := func() int {
+		return lineOf(data, dec.InputOffset())
+	}
+
+	var walk func(tok json.Token, path string) error
+	walk = func(tok json.Token, path string) error {
+		if _, ok := res[path]; !ok {
+			res[path] = line()
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+
+				p := join(path, fmt.Sprint(key))
+				res[p] = line()
+
+				value, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := walk(value, p); err != nil {
+					return err
+				}
+			}
+			_, err := dec.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				value, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := walk(value, index(path, i)); err != nil {
+					return err
+				}
+			}
+			_, err := dec.Token()
+			return err
+		}
+
+		return nil
+	}
+
+	if tok, err := dec.Token(); err == nil {
+		_ = walk(tok, "")
+	}
+
+	return res
+}
+
+// lineOf returns the line of the byte offset.
+func lineOf(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	return bytes.Count(data[:offset], []byte{'\n'}) + 1
+}
+
+// sortErrors sorts the errors by line and path.
+func sortErrors(errs Errors) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Path < errs[j].Path
+	})
+}
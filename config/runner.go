@@ -0,0 +1,227 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/notify"
+	"github.com/whois-api-llc/brand-alert-go/suppress"
+)
+
+// digester is implemented by sinks sending one digest of several notifications.
+type digester interface {
+	SendDigest(ctx context.Context, ns []notify.Notification) error
+}
+
+// Result is the result of the brand search.
+type Result struct {
+	// Brand is the brand name.
+	Brand string
+
+	// Response is the Brand Alert API response without suppressed domains.
+	Response *brandalert.BrandAlertResponse
+
+	// Report is the suppression report.
+	Report suppress.Report
+
+	// Err is the search error.
+	Err error
+}
+
+// Runner searches all configured brands and notifies their targets.
+type Runner struct {
+	// Config is the configuration.
+	Config *Config
+
+	// Client is the Brand Alert API client.
+	Client brandalert.BrandAlert
+
+	// Sinks maps the notification target name to its sink.
+	Sinks map[string]notify.Sink
+
+	// Now returns the current time used to compute lookback dates.
+	// If it's nil then time.Now is used.
+	Now func() time.Time
+
+	suppress map[string]*suppress.List
+}
+
+// NewRunner creates Runner with the sinks of the configured notification
+// targets. Deliveries are recorded by the recorder if it's not nil.
+func NewRunner(cfg *Config, client brandalert.BrandAlert, recorder notify.Recorder) (*Runner, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	sinks, err := cfg.Sinks(recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make(map[string]*suppress.List, len(cfg.Brands))
+
+	for _, b := range cfg.Brands {
+		list, err := cfg.SuppressList(b)
+		if err != nil {
+			return nil, fmt.Errorf("brand %q: %w", b.Name, err)
+		}
+		lists[b.Name] = list
+	}
+
+	return &Runner{
+		Config:   cfg,
+		Client:   client,
+		Sinks:    sinks,
+		suppress: lists,
+	}, nil
+}
+
+// now returns the current time.
+func (r *Runner) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// Run searches all brands, filters out suppressed domains and sends the
+// remaining ones to the brand notification targets. Failed brands don't stop
+// the run: their errors are reported in the results and in the returned error.
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+	results := make([]Result, 0, len(r.Config.Brands))
+
+	var errs []string
+
+	for _, b := range r.Config.Resolved() {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		res := r.RunBrand(ctx, b)
+		results = append(results, res)
+
+		if res.Err != nil {
+			errs = append(errs, fmt.Sprintf("brand %q: %v", b.Name, res.Err))
+		}
+	}
+
+	if err := r.Notify(ctx, results); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return results, errors.New(strings.Join(errs, "; "))
+	}
+
+	return results, nil
+}
+
+// Notify sends the domains of the successful results to the notification
+// targets of their brands. Digest sinks get all brands at once.
+func (r *Runner) Notify(ctx context.Context, results []Result) error {
+	targets := make(map[string][]string)
+	for _, b := range r.Config.Resolved() {
+		targets[b.Name] = b.Notify
+	}
+
+	pending := make(map[string][]notify.Notification)
+
+	for _, res := range results {
+		if res.Err != nil || res.Response == nil || len(res.Response.DomainsList) == 0 {
+			continue
+		}
+
+		for _, name := range targets[res.Brand] {
+			pending[name] = append(pending[name], notify.Notification{
+				Brand: res.Brand,
+				Items: res.Response.DomainsList,
+				Time:  r.now(),
+			})
+		}
+	}
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+
+	for _, name := range names {
+		if err := r.notify(ctx, name, pending[name]); err != nil {
+			errs = append(errs, fmt.Sprintf("notification %q: %v", name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// RunBrand searches the brand and filters out suppressed domains. The
+// options are added to the brand options overriding them.
+func (r *Runner) RunBrand(ctx context.Context, b Brand, opts ...brandalert.Option) Result {
+	res := Result{Brand: b.Name}
+
+	brandOpts, err := b.Options(r.now())
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	opts = append(brandOpts, opts...)
+
+	resp, _, err := r.Client.Purchase(ctx, b.Include.OrNil(), b.Exclude.OrNil(), opts...)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.Response = resp
+
+	list := r.suppress[b.Name]
+	if list == nil {
+		list, err = r.Config.SuppressList(b)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+	}
+
+	res.Response, res.Report = list.Filter(resp)
+
+	return res
+}
+
+// notify sends the notifications to the sink. Digest sinks get all of them at once.
+func (r *Runner) notify(ctx context.Context, name string, ns []notify.Notification) error {
+	sink, ok := r.Sinks[name]
+	if !ok {
+		return errors.New("unknown notification target")
+	}
+
+	if d, ok := sink.(digester); ok {
+		return d.SendDigest(ctx, ns)
+	}
+
+	var errs []string
+
+	for _, n := range ns {
+		if err := sink.Notify(ctx, n); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/notify"
+)
+
+// fakeBrandAlert is the BrandAlert stub returning canned domains per include term.
+type fakeBrandAlert struct {
+	mu       sync.Mutex
+	domains  map[string][]brandalert.DomainItem
+	requests []string
+
+	// emptyExcludes is the number of requests with non-nil empty exclude terms.
+	emptyExcludes int
+}
+
+var _ brandalert.BrandAlert = &fakeBrandAlert{}
+
+func (f *fakeBrandAlert) Purchase(_ context.Context, include *brandalert.SearchTerms, exclude *brandalert.SearchTerms,
+	opts ...brandalert.Option) (*brandalert.BrandAlertResponse, *brandalert.Response, error) {
+	if err := brandalert.ValidateSearchTerms(include, exclude); err != nil {
+		return nil, nil, err
+	}
+
+	term := (*include)[0]
+
+	f.mu.Lock()
+	f.requests = append(f.requests, term)
+	if exclude != nil && len(*exclude) == 0 {
+		f.emptyExcludes++
+	}
+	f.mu.Unlock()
+
+	items, ok := f.domains[term]
+	if !ok {
+		return nil, nil, errors.New("API error")
+	}
+
+	return &brandalert.BrandAlertResponse{DomainsList: items, DomainsCount: len(items)}, nil, nil
+}
+
+func (f *fakeBrandAlert) Preview(context.Context, *brandalert.SearchTerms, *brandalert.SearchTerms, ...brandalert.Option) (int, *brandalert.Response, error) {
+	return 0, nil, nil
+}
+
+func (f *fakeBrandAlert) RawData(context.Context, *brandalert.SearchTerms, *brandalert.SearchTerms, ...brandalert.Option) (*brandalert.Response, error) {
+	return nil, nil
+}
+
+// TestRunner tests running all brands with suppression and notifications.
+func TestRunner(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []notify.Notification
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var n notify.Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			t.Errorf("got body %s", body)
+		}
+
+		mu.Lock()
+		received = append(received, n)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	t.Setenv("BRAND_ALERT_TEST_HOOK", server.URL)
+
+	cfg, err := Parse([]byte(`
+notifications:
+  hook:
+    type: webhook
+    url: ${BRAND_ALERT_TEST_HOOK}
+defaults:
+  notify: [hook]
+brands:
+  - name: WhoisXML
+    include: [whois]
+    lookback: 2d
+    suppress:
+      - type: suffix
+        value: whoisxmlapi.com
+  - name: Acme
+    include: [acme]
+  - name: Broken
+    include: [broken]
+  - name: Quiet
+    include: [quiet]
+`), YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeBrandAlert{
+		domains: map[string][]brandalert.DomainItem{
+			"whois": {
+				{DomainName: "whoisxmlapi.com", Action: brandalert.Added},
+				{DomainName: "whois-login.com", Action: brandalert.Added},
+			},
+			"acme":  {{DomainName: "acme-support.com", Action: brandalert.Added}},
+			"quiet": nil,
+		},
+	}
+
+	log := notify.NewLog(0)
+
+	runner, err := NewRunner(cfg, client, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.Now = func() time.Time { return time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC) }
+
+	results, err := runner.Run(context.Background())
+	checkErr(t, err, `brand "Broken": API error`)
+
+	if len(results) != 4 || results[0].Report.Suppressed != 1 || len(results[0].Response.DomainsList) != 1 ||
+		results[2].Err == nil || results[3].Err != nil {
+		t.Errorf("Run() got = %+v", results)
+	}
+
+	if !reflect.DeepEqual(client.requests, []string{"whois", "acme", "broken", "quiet"}) {
+		t.Errorf("Run() got requests = %v", client.requests)
+	}
+	if client.emptyExcludes != 0 {
+		t.Errorf("Run() sent %d requests with empty exclude terms, want nil", client.emptyExcludes)
+	}
+
+	if len(received) != 2 || received[0].Brand != "WhoisXML" || received[0].Items[0].DomainName != "whois-login.com" ||
+		received[1].Brand != "Acme" {
+		t.Errorf("Run() got notifications = %+v", received)
+	}
+
+	if d := log.Deliveries(); len(d) != 2 || d[0].Sink != "hook" || !d[0].OK() {
+		t.Errorf("Run() got deliveries = %+v", d)
+	}
+}
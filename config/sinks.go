@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/whois-api-llc/brand-alert-go/notify"
+	"github.com/whois-api-llc/brand-alert-go/siem"
+)
+
+// List of notification target types.
+const (
+	TargetWebhook = "webhook"
+	TargetEmail   = "email"
+	TargetSyslog  = "syslog"
+)
+
+// startTLS maps the STARTTLS policy names to policies.
+var startTLS = map[string]notify.StartTLS{
+	"":              notify.StartTLSOpportunistic,
+	"opportunistic": notify.StartTLSOpportunistic,
+	"required":      notify.StartTLSRequired,
+	"disabled":      notify.StartTLSDisabled,
+}
+
+// Sinks creates the sinks of all notification targets. Deliveries are
+// recorded by the recorder if it's not nil.
+func (c *Config) Sinks(recorder notify.Recorder) (map[string]notify.Sink, error) {
+	names := make([]string, 0, len(c.Notifications))
+	for name := range c.Notifications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sinks := make(map[string]notify.Sink, len(names))
+
+	for _, name := range names {
+		target := c.Notifications[name]
+
+		sink, err := newSink(name, target, recorder)
+		if err != nil {
+			return nil, fmt.Errorf("notification target %q: %w", name, err)
+		}
+
+		sinks[name] = sink
+	}
+
+	return sinks, nil
+}
+
+// newSink creates the sink of the notification target.
+func newSink(name string, t Target, recorder notify.Recorder) (notify.Sink, error) {
+	switch t.Type {
+	case TargetWebhook:
+		return notify.NewWebhook(notify.WebhookParams{
+			Name:     name,
+			URL:      t.URL,
+			Template: t.Template,
+			Headers:  t.Headers,
+			Secret:   t.Secret,
+			Recorder: recorder,
+		})
+	case TargetEmail:
+		policy, ok := startTLS[t.StartTLS]
+		if !ok {
+			return nil, fmt.Errorf(`unknown STARTTLS policy "%s"`, t.StartTLS)
+		}
+
+		return notify.NewEmail(notify.EmailParams{
+			Name:       name,
+			Address:    t.Address,
+			Username:   t.Username,
+			Password:   t.Password,
+			StartTLS:   policy,
+			From:       t.From,
+			To:         t.To,
+			Recipients: t.Recipients,
+			Subject:    t.Subject,
+			Recorder:   recorder,
+		})
+	case TargetSyslog:
+		var formatter siem.Formatter
+
+		switch t.Format {
+		case "", "cef":
+			formatter = siem.CEF{}
+		case "leef":
+			formatter = siem.LEEF{}
+		default:
+			return nil, fmt.Errorf(`unknown syslog format "%s"`, t.Format)
+		}
+
+		s, err := siem.NewSyslog(siem.SyslogParams{Network: t.Network, Address: t.Address})
+		if err != nil {
+			return nil, err
+		}
+
+		return &syslogSink{name: name, sink: &siem.Sink{Syslog: s, Formatter: formatter}, recorder: recorder}, nil
+	}
+
+	return nil, fmt.Errorf(`unknown target type "%s"`, t.Type)
+}
+
+// syslogSink adapts siem.Sink to notify.Sink.
+type syslogSink struct {
+	name     string
+	sink     *siem.Sink
+	recorder notify.Recorder
+}
+
+// Notify sends the domains of the notification to syslog.
+func (s *syslogSink) Notify(ctx context.Context, n notify.Notification) error {
+	sink := *s.sink
+	sink.Brand = n.Brand
+
+	delivery := notify.Delivery{
+		Sink:     s.name,
+		Brand:    n.Brand,
+		Items:    n.Count(),
+		Attempts: 1,
+		Time:     time.Now(),
+	}
+
+	err := sink.Send(ctx, n.Items)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	delivery.Duration = time.Since(delivery.Time)
+	if s.recorder != nil {
+		s.recorder.Record(delivery)
+	}
+
+	return err
+}
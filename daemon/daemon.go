@@ -0,0 +1,520 @@
+// Package daemon runs the brand searches of the multi-brand configuration on
+// their schedules, remembers the last successful search of every brand across
+// restarts and reports its health over HTTP.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/config"
+	"github.com/whois-api-llc/brand-alert-go/notify"
+	"github.com/whois-api-llc/brand-alert-go/schedule"
+	"github.com/whois-api-llc/brand-alert-go/store"
+)
+
+// DefaultSchedule is the schedule of the brands without one.
+const DefaultSchedule = "@daily"
+
+// defaultShutdownTimeout is the default time to wait for running searches on shutdown.
+const defaultShutdownTimeout = 30 * time.Second
+
+// Params is used to create Daemon.
+type Params struct {
+	// ConfigPath is the path of the multi-brand configuration file.
+	ConfigPath string
+
+	// StatePath is the path of the state file. It's ignored if Store is set.
+	StatePath string
+
+	// Store keeps the state of every brand under its own key if it's not nil.
+	// Daemon does not close it.
+	Store store.Store
+
+	// Client is the Brand Alert API client.
+	Client brandalert.BrandAlert
+
+	// Recorder records the notification deliveries if it's not nil.
+	Recorder notify.Recorder
+
+	// Logger logs the searches and reloads. If it's nil then nothing is logged.
+	Logger *log.Logger
+
+	// ShutdownTimeout is the time to wait for running searches on shutdown
+	// before they are canceled. Default: 30s.
+	ShutdownTimeout time.Duration
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+// saveState writes the state of the brand. It must be called with the lock held.
+func (d *Daemon) saveState(name string) error {
+	if d.params.Store != nil {
+		// The state is saved even if the run has been canceled.
+		return d.state.SaveBrand(context.Background(), d.params.Store, name)
+	}
+
+	return d.state.Save(d.params.StatePath)
+}
+
+// entry is the scheduled brand.
+type entry struct {
+	brand    config.Brand
+	schedule schedule.Schedule
+	jitter   time.Duration
+	next     time.Time
+}
+
+// Daemon runs the configured brand searches on their schedules.
+type Daemon struct {
+	params Params
+	logger *log.Logger
+
+	mu          sync.Mutex
+	runner      *config.Runner
+	entries     []*entry
+	state       *State
+	ready       bool
+	lastReload  time.Time
+	reloadError string
+
+	reloaded chan struct{}
+}
+
+// New creates Daemon loading its state and configuration.
+func New(params Params) (*Daemon, error) {
+	var (
+		state *State
+		err   error
+	)
+
+	if params.Store != nil {
+		state, err = LoadStore(context.Background(), params.Store)
+	} else {
+		state, err = LoadState(params.StatePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Daemon{
+		params:   params,
+		logger:   params.Logger,
+		state:    state,
+		reloaded: make(chan struct{}, 1),
+	}
+
+	if d.logger == nil {
+		d.logger = log.New(io.Discard, "", 0)
+	}
+
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// now returns the current time.
+func (d *Daemon) now() time.Time {
+	if d.params.Now != nil {
+		return d.params.Now()
+	}
+	return time.Now()
+}
+
+// Reload loads the configuration file again. If it's invalid then the
+// current configuration is kept. Brands with unchanged schedules keep their
+// next run times.
+func (d *Daemon) Reload() error {
+	now := d.now()
+
+	cfg, err := config.Load(d.params.ConfigPath)
+	if err == nil {
+		var runner *config.Runner
+		if runner, err = config.NewRunner(cfg, d.params.Client, d.params.Recorder); err == nil {
+			err = d.apply(runner, now)
+		}
+	}
+
+	d.mu.Lock()
+	d.lastReload = now
+	d.reloadError = ""
+	if err != nil {
+		d.reloadError = err.Error()
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("cannot reload configuration: %w", err)
+	}
+
+	d.logger.Printf("loaded configuration %s", d.params.ConfigPath)
+
+	select {
+	case d.reloaded <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// apply replaces the runner and the scheduled brands.
+func (d *Daemon) apply(runner *config.Runner, now time.Time) error {
+	brands := runner.Config.Resolved()
+	entries := make([]*entry, 0, len(brands))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, b := range brands {
+		if b.Schedule == "" {
+			b.Schedule = DefaultSchedule
+		}
+
+		s, err := schedule.Parse(b.Schedule)
+		if err != nil {
+			return fmt.Errorf("brand %q: %w", b.Name, err)
+		}
+
+		var jitter time.Duration
+		if b.Jitter != "" {
+			if jitter, err = time.ParseDuration(b.Jitter); err != nil {
+				return fmt.Errorf("brand %q: %w", b.Name, err)
+			}
+		}
+
+		e := &entry{brand: b, schedule: s, jitter: jitter}
+
+		if old := d.entry(b.Name); old != nil && old.brand.Schedule == b.Schedule && old.jitter == jitter {
+			e.next = old.next
+		} else {
+			e.next = d.firstRun(e, now)
+		}
+
+		entries = append(entries, e)
+	}
+
+	d.runner = runner
+	d.entries = entries
+
+	return nil
+}
+
+// firstRun returns the first run time of the brand. Brands which never ran
+// or missed their scheduled run while the daemon was stopped run immediately.
+func (d *Daemon) firstRun(e *entry, now time.Time) time.Time {
+	st, ok := d.state.Brands[e.brand.Name]
+	if !ok || st.LastRun.IsZero() {
+		return now
+	}
+
+	next := schedule.Jitter(e.schedule.Next(st.LastRun), e.jitter)
+	if next.Before(now) {
+		return now
+	}
+
+	return next
+}
+
+// entry returns the scheduled brand by its name.
+func (d *Daemon) entry(name string) *entry {
+	for _, e := range d.entries {
+		if e.brand.Name == name {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Run runs the brand searches on their schedules until the context is
+// canceled. Then it waits for the running search up to ShutdownTimeout.
+func (d *Daemon) Run(ctx context.Context) error {
+	d.setReady(true)
+	defer d.setReady(false)
+
+	// Searches are not canceled with ctx to let them finish on shutdown.
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		var (
+			timer *time.Timer
+			wait  <-chan time.Time
+		)
+
+		if next, ok := d.nextRun(); ok {
+			timer = time.NewTimer(next.Sub(d.now()))
+			wait = timer.C
+		}
+
+		due := false
+
+		select {
+		case <-ctx.Done():
+		case <-d.reloaded:
+		case <-wait:
+			due = true
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !due {
+			// Reloaded: recompute the next run time.
+			continue
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			d.runDue(ctx, runCtx)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			d.setReady(false)
+			d.logger.Printf("waiting for running searches")
+
+			timeout := d.params.ShutdownTimeout
+			if timeout <= 0 {
+				timeout = defaultShutdownTimeout
+			}
+
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+
+			select {
+			case <-done:
+			case <-timer.C:
+				cancel()
+				<-done
+			}
+
+			return nil
+		}
+	}
+}
+
+// nextRun returns the earliest next run time of the brands.
+func (d *Daemon) nextRun() (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var next time.Time
+	for _, e := range d.entries {
+		if next.IsZero() || e.next.Before(next) {
+			next = e.next
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// runDue runs the brands whose next run time has come. No more brands are
+// started after stop is canceled.
+func (d *Daemon) runDue(stop, ctx context.Context) {
+	now := d.now()
+
+	var due []string
+
+	d.mu.Lock()
+	for _, e := range d.entries {
+		if !e.next.After(now) {
+			due = append(due, e.brand.Name)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, name := range due {
+		if stop.Err() != nil {
+			return
+		}
+
+		if err := d.RunBrand(ctx, name); err != nil {
+			d.logger.Printf("brand %q: %v", name, err)
+		}
+
+		d.mu.Lock()
+		if e := d.entry(name); e != nil {
+			e.next = schedule.Jitter(e.schedule.Next(d.now()), e.jitter)
+		}
+		d.mu.Unlock()
+	}
+}
+
+// RunBrand searches the brand since its last successful search and notifies
+// its targets about the domains which were not notified yet. The brand state
+// is saved after the search.
+func (d *Daemon) RunBrand(ctx context.Context, name string) error {
+	now := d.now()
+
+	d.mu.Lock()
+	runner, e := d.runner, d.entry(name)
+	var st BrandState
+	if e != nil {
+		st = *d.state.Brand(name)
+	}
+	d.mu.Unlock()
+
+	if e == nil {
+		return fmt.Errorf("unknown brand %q", name)
+	}
+
+	var opts []brandalert.Option
+
+	since := ""
+	if !st.LastSuccess.IsZero() {
+		date := st.LastSuccess.UTC()
+		since = date.Format(dateFormat)
+		opts = append(opts, brandalert.OptionSinceDate(date))
+	} else if lookback, err := config.ParseLookback(e.brand.Lookback); err == nil && lookback > 0 {
+		since = now.Add(-lookback).Format(dateFormat)
+	}
+
+	res := runner.RunBrand(ctx, e.brand, opts...)
+
+	var found, notified int
+
+	err := res.Err
+	if err == nil {
+		found = len(res.Response.DomainsList)
+
+		items := st.Unseen(res.Response.DomainsList)
+		notified = len(items)
+
+		err = runner.Notify(ctx, []config.Result{{
+			Brand:    name,
+			Response: &brandalert.BrandAlertResponse{DomainsList: items, DomainsCount: len(items)},
+		}})
+	}
+
+	d.mu.Lock()
+	state := d.state.Brand(name)
+	state.LastRun = now
+	if err != nil {
+		state.LastError = err.Error()
+	} else {
+		state.LastSuccess = now
+		state.SinceDate = since
+		state.LastError = ""
+		state.MarkSeen(res.Response.DomainsList, since)
+	}
+	saveErr := d.saveState(name)
+	d.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	d.logger.Printf("brand %q: found %d domains, notified %d", name, found, notified)
+
+	return saveErr
+}
+
+// setReady sets the readiness of the daemon.
+func (d *Daemon) setReady(ready bool) {
+	d.mu.Lock()
+	d.ready = ready
+	d.mu.Unlock()
+}
+
+// BrandStatus is the status of the scheduled brand.
+type BrandStatus struct {
+	BrandState
+
+	// Name is the brand name.
+	Name string `json:"name"`
+
+	// Schedule is the schedule expression.
+	Schedule string `json:"schedule"`
+
+	// NextRun is the time of the next search.
+	NextRun time.Time `json:"nextRun"`
+}
+
+// Status is the status of the daemon.
+type Status struct {
+	// Ready reports whether the daemon runs the schedule.
+	Ready bool `json:"ready"`
+
+	// LastReload is the time of the last configuration reload.
+	LastReload time.Time `json:"lastReload"`
+
+	// ReloadError is the error of the last configuration reload if it failed.
+	ReloadError string `json:"reloadError,omitempty"`
+
+	// Brands is the status of the scheduled brands sorted by the name.
+	Brands []BrandStatus `json:"brands"`
+}
+
+// Status returns the current status of the daemon.
+func (d *Daemon) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := Status{
+		Ready:       d.ready,
+		LastReload:  d.lastReload,
+		ReloadError: d.reloadError,
+		Brands:      make([]BrandStatus, 0, len(d.entries)),
+	}
+
+	for _, e := range d.entries {
+		bs := BrandStatus{Name: e.brand.Name, Schedule: e.brand.Schedule, NextRun: e.next}
+		if st, ok := d.state.Brands[e.brand.Name]; ok {
+			bs.BrandState = *st
+			bs.Seen = nil
+		}
+		status.Brands = append(status.Brands, bs)
+	}
+
+	sort.Slice(status.Brands, func(i, j int) bool {
+		return status.Brands[i].Name < status.Brands[j].Name
+	})
+
+	return status
+}
+
+// Handler returns the HTTP handler serving the health endpoints:
+// /healthz responds 200 while the process is alive, /readyz responds 200
+// while the schedule runs and 503 otherwise, /status returns Status as JSON.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok\n")
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !d.Status().Ready {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok\n")
+	})
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(d.Status())
+	})
+
+	return mux
+}
@@ -0,0 +1,290 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/store"
+)
+
+// apiServer is the Brand Alert API stub recording the sinceDate of requests.
+type apiServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	since []string
+}
+
+func newAPIServer() *apiServer {
+	s := &apiServer{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SinceDate string `json:"sinceDate"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		s.mu.Lock()
+		s.since = append(s.since, req.SinceDate)
+		s.mu.Unlock()
+
+		today := time.Now().UTC().Format(dateFormat)
+
+		_, _ = io.WriteString(w, `{"domainsCount":2,"domainsList":[
+{"domainName":"whois-login.com","date":"`+today+`","action":"added"},
+{"domainName":"whoisxml.app","date":"`+today+`","action":"discovered"}]}`)
+	}))
+
+	return s
+}
+
+func (s *apiServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.since...)
+}
+
+func (s *apiServer) client() brandalert.BrandAlert {
+	u, _ := url.Parse(s.URL)
+	return brandalert.NewClient("key", brandalert.ClientParams{BrandAlertBaseURL: u})
+}
+
+// writeConfig writes the configuration file.
+func writeConfig(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestDaemon tests scheduled searches, deduplication of notified domains,
+// persisted state and health endpoints.
+func TestDaemon(t *testing.T) {
+	api := newAPIServer()
+	defer api.Close()
+
+	var (
+		mu       sync.Mutex
+		notified int
+	)
+
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		notified++
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	dir := t.TempDir()
+	configPath, statePath := filepath.Join(dir, "brands.yaml"), filepath.Join(dir, "state.json")
+
+	writeConfig(t, configPath, `
+notifications:
+  hook:
+    type: webhook
+    url: `+hook.URL+`
+brands:
+  - name: WhoisXML
+    include: [whois]
+    lookback: 2d
+    schedule: "@every 20ms"
+    notify: [hook]
+`)
+
+	d, err := New(Params{ConfigPath: configPath, StatePath: statePath, Client: api.client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := d.Handler()
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if got := get("/healthz"); got != http.StatusOK {
+		t.Errorf("/healthz got = %v", got)
+	}
+	if got := get("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before Run got = %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+
+	for deadline := time.Now().Add(5 * time.Second); len(api.requests()) < 3; {
+		if time.Now().After(deadline) {
+			t.Fatal("searches did not run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := get("/readyz"); got != http.StatusOK {
+		t.Errorf("/readyz got = %v", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if got := get("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz after Run got = %v", got)
+	}
+
+	now := time.Now().UTC()
+	requests := api.requests()
+	if requests[0] != now.AddDate(0, 0, -2).Format(dateFormat) || requests[1] != now.Format(dateFormat) {
+		t.Errorf("got sinceDate = %v", requests)
+	}
+
+	mu.Lock()
+	if notified != 1 {
+		t.Errorf("got %d notifications, want 1", notified)
+	}
+	mu.Unlock()
+
+	state, err := LoadState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := state.Brands["WhoisXML"]; st == nil || st.LastSuccess.IsZero() || st.LastError != "" || len(st.Seen) != 2 {
+		t.Errorf("LoadState() got = %+v", st)
+	}
+
+	status := d.Status()
+	if len(status.Brands) != 1 || status.Brands[0].Schedule != "@every 20ms" || status.Brands[0].Seen != nil {
+		t.Errorf("Status() got = %+v", status)
+	}
+}
+
+// TestDaemonStore tests keeping the state in store.Store instead of the state file.
+func TestDaemonStore(t *testing.T) {
+	api := newAPIServer()
+	defer api.Close()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "brands.yaml")
+
+	writeConfig(t, configPath, "brands:\n  - name: WhoisXML\n    include: [whois]\n")
+
+	s := store.NewMemory()
+
+	for i := 0; i < 2; i++ {
+		d, err := New(Params{ConfigPath: configPath, StatePath: filepath.Join(dir, "state.json"), Store: s, Client: api.client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := d.RunBrand(context.Background(), "WhoisXML"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "state.json")); !os.IsNotExist(err) {
+		t.Errorf("state file exists, error = %v", err)
+	}
+
+	state, err := LoadStore(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := state.Brands["WhoisXML"]; st == nil || st.LastSuccess.IsZero() || len(st.Seen) != 2 {
+		t.Errorf("LoadStore() got = %+v", st)
+	}
+
+	// The second run searches since the date of the first one loaded from the store.
+	today := time.Now().UTC().Format(dateFormat)
+	if requests := api.requests(); len(requests) != 2 || requests[1] != today {
+		t.Errorf("got sinceDate = %v", requests)
+	}
+}
+
+// TestReload tests keeping the configuration on invalid reloads and the
+// first run times after restarts.
+func TestReload(t *testing.T) {
+	api := newAPIServer()
+	defer api.Close()
+
+	now := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	configPath, statePath := filepath.Join(dir, "brands.yaml"), filepath.Join(dir, "state.json")
+
+	state := &State{Brands: map[string]*BrandState{
+		"WhoisXML": {LastRun: now.Add(-time.Hour)},
+		"Acme":     {LastRun: now.Add(-25 * time.Hour)},
+	}}
+	if err := state.Save(statePath); err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig(t, configPath, "brands:\n  - name: WhoisXML\n    include: [whois]\n")
+
+	d, err := New(Params{
+		ConfigPath: configPath,
+		StatePath:  statePath,
+		Client:     api.client(),
+		Now:        func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig(t, configPath, "brands:\n  - name: WhoisXML\n    include: [whois]\n    schedule: daily\n")
+
+	if err := d.Reload(); err == nil {
+		t.Error("Reload() expected error")
+	}
+
+	writeConfig(t, configPath, `
+defaults:
+  schedule: "0 6 * * *"
+brands:
+  - name: WhoisXML
+    include: [whois]
+    schedule: "@daily"
+  - name: Acme
+    include: [acme]
+  - name: New
+    include: [new]
+`)
+
+	if err := d.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	status := d.Status()
+
+	want := []struct {
+		name, next string
+	}{
+		{name: "Acme", next: "2022-11-01T10:00:00Z"},
+		{name: "New", next: "2022-11-01T10:00:00Z"},
+		{name: "WhoisXML", next: "2022-11-02T00:00:00Z"},
+	}
+
+	if len(status.Brands) != len(want) || status.ReloadError != "" {
+		t.Fatalf("Status() got = %+v", status)
+	}
+
+	for i, w := range want {
+		if b := status.Brands[i]; b.Name != w.name || b.NextRun.Format(time.RFC3339) != w.next {
+			t.Errorf("Status() got brand = %v next %v, want %v next %v", b.Name, b.NextRun, w.name, w.next)
+		}
+	}
+}
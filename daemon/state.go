@@ -0,0 +1,186 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/internal/atomicfile"
+	"github.com/whois-api-llc/brand-alert-go/store"
+)
+
+// brandKeyPrefix is the prefix of the brand state keys in store.Store.
+const brandKeyPrefix = "daemon/brands/"
+
+// dateFormat is the format of the sinceDate option.
+const dateFormat = "2006-01-02"
+
+// BrandState is the persisted state of the brand searches.
+type BrandState struct {
+	// LastRun is the time of the last search.
+	LastRun time.Time `json:"lastRun,omitempty"`
+
+	// LastSuccess is the time of the last successful search and notification.
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+
+	// SinceDate is the sinceDate of the last successful search.
+	SinceDate string `json:"sinceDate,omitempty"`
+
+	// LastError is the error of the last search if it failed.
+	LastError string `json:"lastError,omitempty"`
+
+	// Seen is the set of already notified activities since SinceDate.
+	Seen map[string]bool `json:"seen,omitempty"`
+}
+
+// State is the persisted state of all brands.
+type State struct {
+	// Brands maps the brand name to its state.
+	Brands map[string]*BrandState `json:"brands"`
+}
+
+// LoadState reads the state file. A missing file results in the empty state.
+func LoadState(path string) (*State, error) {
+	state := &State{Brands: make(map[string]*BrandState)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("cannot parse state %s: %w", path, err)
+	}
+
+	if state.Brands == nil {
+		state.Brands = make(map[string]*BrandState)
+	}
+
+	return state, nil
+}
+
+// Save writes the state file atomically.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal state: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("cannot write state: %w", err)
+	}
+
+	return nil
+}
+
+// LoadStore reads the state of all brands from the store.
+func LoadStore(ctx context.Context, s store.Store) (*State, error) {
+	state := &State{Brands: make(map[string]*BrandState)}
+
+	keys, err := s.Keys(ctx, brandKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read state: %w", err)
+	}
+
+	for _, key := range keys {
+		data, err := s.Get(ctx, key)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read state: %w", err)
+		}
+
+		var st BrandState
+		if err := json.Unmarshal(data, &st); err != nil {
+			return nil, fmt.Errorf("cannot parse state %s: %w", key, err)
+		}
+
+		state.Brands[key[len(brandKeyPrefix):]] = &st
+	}
+
+	return state, nil
+}
+
+// SaveBrand writes the state of the brand to the store.
+func (s *State) SaveBrand(ctx context.Context, st store.Store, name string) error {
+	data, err := json.Marshal(s.Brand(name))
+	if err != nil {
+		return fmt.Errorf("cannot marshal state: %w", err)
+	}
+
+	if err := st.Set(ctx, brandKeyPrefix+name, data, 0); err != nil {
+		return fmt.Errorf("cannot write state: %w", err)
+	}
+
+	return nil
+}
+
+// Brand returns the state of the brand creating it if needed.
+func (s *State) Brand(name string) *BrandState {
+	st, ok := s.Brands[name]
+	if !ok {
+		st = &BrandState{}
+		s.Brands[name] = st
+	}
+
+	return st
+}
+
+// seenKey returns the key of the activity in the Seen set. Keys start with
+// the activity date so that old ones can be pruned. Keys of undated
+// activities start with the separator.
+func seenKey(item brandalert.DomainItem) string {
+	var date string
+	if !item.Date.IsZero() {
+		date = time.Time(item.Date).UTC().Format(dateFormat)
+	}
+
+	return date + "|" + string(item.Action) + "|" + item.DomainName
+}
+
+// Unseen returns the items that were not notified yet.
+func (st *BrandState) Unseen(items []brandalert.DomainItem) []brandalert.DomainItem {
+	var unseen []brandalert.DomainItem
+
+	for _, item := range items {
+		if !st.Seen[seenKey(item)] {
+			unseen = append(unseen, item)
+		}
+	}
+
+	return unseen
+}
+
+// MarkSeen adds the items to the Seen set and removes activities older than
+// the since date. Undated activities can not be pruned by date, so they are
+// kept as long as the search returns them.
+func (st *BrandState) MarkSeen(items []brandalert.DomainItem, since string) {
+	if st.Seen == nil {
+		st.Seen = make(map[string]bool)
+	}
+
+	current := make(map[string]bool, len(items))
+
+	for _, item := range items {
+		key := seenKey(item)
+		st.Seen[key] = true
+		current[key] = true
+	}
+
+	for key := range st.Seen {
+		date := key[:strings.IndexByte(key, '|')]
+		if date == "" && !current[key] || date != "" && date < since {
+			delete(st.Seen, key)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/store"
+)
+
+// TestState tests saving, loading and pruning the state.
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Brands) != 0 {
+		t.Errorf("LoadState() got = %+v", state)
+	}
+
+	date := func(s string) brandalert.Time {
+		v, err := brandalert.ParseTime(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	items := []brandalert.DomainItem{
+		{DomainName: "whois-login.com", Action: brandalert.Added, Date: date("2022-10-30")},
+		{DomainName: "whois-login.com", Action: brandalert.Dropped, Date: date("2022-11-01")},
+	}
+
+	st := state.Brand("WhoisXML")
+	st.LastSuccess = time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+	st.MarkSeen(items, "2022-10-30")
+
+	if got := st.Unseen(append(items, brandalert.DomainItem{DomainName: "whois.app", Action: brandalert.Added})); len(got) != 1 ||
+		got[0].DomainName != "whois.app" {
+		t.Errorf("Unseen() got = %+v", got)
+	}
+
+	st.MarkSeen(nil, "2022-10-31")
+	if got := st.Unseen(items); len(got) != 1 || got[0].Action != brandalert.Added {
+		t.Errorf("Unseen() after pruning got = %+v", got)
+	}
+
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("LoadState() got = %+v, want %+v", loaded.Brands["WhoisXML"], st)
+	}
+}
+
+// TestMarkSeenUndated tests that undated items are not notified again while
+// the search returns them.
+func TestMarkSeenUndated(t *testing.T) {
+	undated := []brandalert.DomainItem{
+		{DomainName: "whois.app", Action: brandalert.Added},
+		{DomainName: "whois.dev", Action: brandalert.Added},
+	}
+
+	st := &BrandState{}
+	st.MarkSeen(undated, "2022-10-30")
+
+	if got := st.Unseen(undated); len(got) != 0 {
+		t.Errorf("Unseen() got = %+v, want none", got)
+	}
+
+	st.MarkSeen(undated[1:], "2022-10-31")
+	if got := st.Unseen(undated); len(got) != 1 || got[0].DomainName != "whois.app" {
+		t.Errorf("Unseen() after pruning got = %+v", got)
+	}
+}
+
+// TestStateStore tests saving and loading the state of the brands in store.Store.
+func TestStateStore(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemory()
+
+	state, err := LoadStore(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Brands) != 0 {
+		t.Errorf("LoadStore() got = %+v", state)
+	}
+
+	st := state.Brand("WhoisXML")
+	st.LastSuccess = time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+	st.MarkSeen([]brandalert.DomainItem{{DomainName: "whois.app", Action: brandalert.Added}}, "2022-10-30")
+	state.Brand("Acme").LastError = "unavailable"
+
+	for _, name := range []string{"WhoisXML", "Acme"} {
+		if err := state.SaveBrand(ctx, s, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if keys, _ := s.Keys(ctx, ""); !reflect.DeepEqual(keys, []string{"daemon/brands/Acme", "daemon/brands/WhoisXML"}) {
+		t.Errorf("Keys() got = %v", keys)
+	}
+
+	loaded, err := LoadStore(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("LoadStore() got = %+v, want %+v", loaded.Brands, state.Brands)
+	}
+
+	_ = s.Set(ctx, "daemon/brands/Broken", []byte("{"), 0)
+	if _, err := LoadStore(ctx, s); err == nil {
+		t.Error("LoadStore() of the broken state expected error")
+	}
+}
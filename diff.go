@@ -0,0 +1,186 @@
+package brandalert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DomainChange is the change of the domain present in both responses.
+type DomainChange struct {
+	// DomainName is the full domain name.
+	DomainName string `json:"domainName"`
+
+	// Old is the domain in the old response.
+	Old DomainItem `json:"old"`
+
+	// New is the domain in the new response.
+	New DomainItem `json:"new"`
+}
+
+// ActionChanged reports whether the action has changed.
+func (c DomainChange) ActionChanged() bool {
+	return c.Old.Action != c.New.Action
+}
+
+// DateChanged reports whether the date has changed.
+func (c DomainChange) DateChanged() bool {
+	return !c.Old.Date.Equal(c.New.Date)
+}
+
+// ResponseDiff is the difference between two Brand Alert API responses.
+// All lists are sorted by domain name.
+type ResponseDiff struct {
+	// Appeared is the list of domains present only in the new response.
+	Appeared []DomainItem `json:"appeared"`
+
+	// Disappeared is the list of domains present only in the old response.
+	Disappeared []DomainItem `json:"disappeared"`
+
+	// Changed is the list of domains with changed action or date.
+	Changed []DomainChange `json:"changed"`
+}
+
+// Empty reports whether the responses have no differences.
+func (d *ResponseDiff) Empty() bool {
+	return len(d.Appeared) == 0 && len(d.Disappeared) == 0 && len(d.Changed) == 0
+}
+
+// String returns the human-readable representation of the difference.
+func (d *ResponseDiff) String() string {
+	var b strings.Builder
+
+	for _, item := range d.Appeared {
+		fmt.Fprintf(&b, "+ %s %s %s\n", item.DomainName, item.Action, formatDate(item.Date))
+	}
+
+	for _, item := range d.Disappeared {
+		fmt.Fprintf(&b, "- %s %s %s\n", item.DomainName, item.Action, formatDate(item.Date))
+	}
+
+	for _, c := range d.Changed {
+		var changes []string
+		if c.ActionChanged() {
+			changes = append(changes, fmt.Sprintf("action %s -> %s", c.Old.Action, c.New.Action))
+		}
+		if c.DateChanged() {
+			changes = append(changes, fmt.Sprintf("date %s -> %s", formatDate(c.Old.Date), formatDate(c.New.Date)))
+		}
+		fmt.Fprintf(&b, "~ %s %s\n", c.DomainName, strings.Join(changes, ", "))
+	}
+
+	return b.String()
+}
+
+// Diff returns the difference between the old and the new responses. Domains
+// are compared by case-insensitive names. If a response contains the domain
+// more than once, the latest event is used. Nil responses are treated as empty.
+func Diff(oldResp, newResp *BrandAlertResponse) *ResponseDiff {
+	oldItems, newItems := latestByName(oldResp), latestByName(newResp)
+
+	diff := &ResponseDiff{
+		Appeared:    []DomainItem{},
+		Disappeared: []DomainItem{},
+		Changed:     []DomainChange{},
+	}
+
+	for name, newItem := range newItems {
+		oldItem, ok := oldItems[name]
+		if !ok {
+			diff.Appeared = append(diff.Appeared, newItem)
+			continue
+		}
+
+		change := DomainChange{DomainName: newItem.DomainName, Old: oldItem, New: newItem}
+		if change.ActionChanged() || change.DateChanged() {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	for name, oldItem := range oldItems {
+		if _, ok := newItems[name]; !ok {
+			diff.Disappeared = append(diff.Disappeared, oldItem)
+		}
+	}
+
+	sortItems(diff.Appeared)
+	sortItems(diff.Disappeared)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].DomainName < diff.Changed[j].DomainName
+	})
+
+	return diff
+}
+
+// ReadSnapshot reads the response stored as JSON, e.g. the raw API response.
+func ReadSnapshot(r io.Reader) (*BrandAlertResponse, error) {
+	var resp BrandAlertResponse
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("cannot parse snapshot: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// DiffSnapshots returns the difference between two responses stored as JSON files.
+func DiffSnapshots(oldPath, newPath string) (*ResponseDiff, error) {
+	oldResp, err := readSnapshotFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+
+	newResp, err := readSnapshotFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return Diff(oldResp, newResp), nil
+}
+
+// readSnapshotFile reads the response stored as JSON file.
+func readSnapshotFile(path string) (*BrandAlertResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	return ReadSnapshot(f)
+}
+
+// latestByName returns the latest event of every domain by its lower case name.
+func latestByName(resp *BrandAlertResponse) map[string]DomainItem {
+	items := make(map[string]DomainItem)
+	if resp == nil {
+		return items
+	}
+
+	for _, item := range resp.DomainsList {
+		name := strings.ToLower(item.DomainName)
+		if prev, ok := items[name]; ok && prev.Date.After(item.Date) {
+			continue
+		}
+		items[name] = item
+	}
+
+	return items
+}
+
+// sortItems sorts the items by domain name.
+func sortItems(items []DomainItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DomainName < items[j].DomainName
+	})
+}
+
+// formatDate returns the date as Brand Alert API does.
+func formatDate(t Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.String()
+}
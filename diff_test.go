@@ -0,0 +1,80 @@
+package brandalert
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	snapshotOld = `{"domainsCount":4,"domainsList":[
+{"domainName":"batchwhois.com","date":"2022-10-29","action":"added"},
+{"domainName":"betterwhoislookup.com","date":"2022-10-29","action":"discovered"},
+{"domainName":"whoisdomainlookup.info","date":"2022-10-29","action":"added"},
+{"domainName":"whoisdodster.com","date":"2022-10-29","action":"added"}]}`
+
+	snapshotNew = `{"domainsCount":4,"domainsList":[
+{"domainName":"BatchWhois.com","date":"2022-10-30","action":"dropped"},
+{"domainName":"betterwhoislookup.com","date":"2022-10-29","action":"discovered"},
+{"domainName":"whoisdomainlookup.info","date":"2022-10-30","action":"added"},
+{"domainName":"whoisdomainlookup.info","date":"2022-10-28","action":"updated"},
+{"domainName":"whoisfinder.net","date":"2022-10-30","action":"added"}]}`
+)
+
+// TestDiff tests the Diff function and its renderings.
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+
+	oldPath, newPath := filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")
+	if err := os.WriteFile(oldPath, []byte(snapshotOld), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, []byte(snapshotNew), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffSnapshots(oldPath, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const wantText = `+ whoisfinder.net added 2022-10-30
+- whoisdodster.com added 2022-10-29
+~ BatchWhois.com action added -> dropped, date 2022-10-29 -> 2022-10-30
+~ whoisdomainlookup.info date 2022-10-29 -> 2022-10-30
+`
+	if got := diff.String(); got != wantText {
+		t.Errorf("String() got = %v, want %v", got, wantText)
+	}
+
+	const wantJSON = `{"appeared":[{"domainName":"whoisfinder.net","action":"added","date":"2022-10-30"}],` +
+		`"disappeared":[{"domainName":"whoisdodster.com","action":"added","date":"2022-10-29"}],` +
+		`"changed":[{"domainName":"BatchWhois.com",` +
+		`"old":{"domainName":"batchwhois.com","action":"added","date":"2022-10-29"},` +
+		`"new":{"domainName":"BatchWhois.com","action":"dropped","date":"2022-10-30"}},` +
+		`{"domainName":"whoisdomainlookup.info",` +
+		`"old":{"domainName":"whoisdomainlookup.info","action":"added","date":"2022-10-29"},` +
+		`"new":{"domainName":"whoisdomainlookup.info","action":"added","date":"2022-10-30"}}]}`
+
+	bb, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != wantJSON {
+		t.Errorf("json.Marshal() got = %v, want %v", string(bb), wantJSON)
+	}
+
+	if diff.Empty() {
+		t.Errorf("Empty() got = true, want false")
+	}
+
+	if d := Diff(nil, nil); !d.Empty() || d.String() != "" {
+		t.Errorf("Diff() got = %v, want empty", d)
+	}
+
+	_, err = DiffSnapshots(filepath.Join(dir, "missing.json"), newPath)
+	if err == nil {
+		t.Errorf("DiffSnapshots() expected error for missing file")
+	}
+}
@@ -0,0 +1,63 @@
+package brandalert
+
+import (
+	"strings"
+
+	"github.com/whois-api-llc/brand-alert-go/publicsuffix"
+)
+
+// Parts splits the domain name into the subdomain, the registrable domain
+// and the public suffix using the Public Suffix List.
+func (d DomainItem) Parts() publicsuffix.Parts {
+	return publicsuffix.Split(d.DomainName)
+}
+
+// RegistrableDomain returns the registrable domain, e.g. "example.co.uk".
+func (d DomainItem) RegistrableDomain() string {
+	return d.Parts().RegistrableDomain
+}
+
+// PublicSuffix returns the public suffix, e.g. "co.uk".
+func (d DomainItem) PublicSuffix() string {
+	return d.Parts().PublicSuffix
+}
+
+// Subdomain returns the part of the domain name left of the registrable domain.
+func (d DomainItem) Subdomain() string {
+	return d.Parts().Subdomain
+}
+
+// GroupByRegistrableDomain groups the domains by the registrable domain.
+// Domains which are public suffixes themselves are grouped under the empty key.
+func (r *BrandAlertResponse) GroupByRegistrableDomain() map[string][]DomainItem {
+	return r.groupBy(DomainItem.RegistrableDomain)
+}
+
+// GroupByPublicSuffix groups the domains by the public suffix.
+func (r *BrandAlertResponse) GroupByPublicSuffix() map[string][]DomainItem {
+	return r.groupBy(DomainItem.PublicSuffix)
+}
+
+// FilterRegistrableDomain returns a copy of the response with the domains
+// having one of the specified registrable domains.
+func (r *BrandAlertResponse) FilterRegistrableDomain(domains ...string) *BrandAlertResponse {
+	set := toSet(domains, func(s string) string {
+		return publicsuffix.RegistrableDomain(s)
+	})
+
+	return r.Filter(func(item DomainItem) bool {
+		return set[item.RegistrableDomain()]
+	})
+}
+
+// FilterPublicSuffix returns a copy of the response with the domains having
+// one of the specified public suffixes, e.g. "com" or "co.uk".
+func (r *BrandAlertResponse) FilterPublicSuffix(suffixes ...string) *BrandAlertResponse {
+	set := toSet(suffixes, func(s string) string {
+		return strings.TrimPrefix(strings.ToLower(s), ".")
+	})
+
+	return r.Filter(func(item DomainItem) bool {
+		return set[item.PublicSuffix()]
+	})
+}
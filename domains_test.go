@@ -0,0 +1,110 @@
+package brandalert
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var testDomains = &BrandAlertResponse{
+	DomainsList: []DomainItem{
+		{DomainName: "whois.co.uk", Action: Added},
+		{DomainName: "api.whois.co.uk", Action: Updated},
+		{DomainName: "whois.com", Action: Added},
+		{DomainName: "login.whois.com", Action: Dropped},
+		{DomainName: "whois.github.io", Action: Discovered},
+	},
+	DomainsCount: 5,
+}
+
+// domainNames returns the domain names of the items.
+func domainNames(items []DomainItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.DomainName)
+	}
+
+	return names
+}
+
+// TestDomainItemParts tests the domain name parts functions.
+func TestDomainItemParts(t *testing.T) {
+	item := DomainItem{DomainName: "api.whois.co.uk"}
+
+	got := []string{item.Subdomain(), item.RegistrableDomain(), item.PublicSuffix()}
+	want := []string{"api", "whois.co.uk", "co.uk"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parts() got = %v, want %v", got, want)
+	}
+}
+
+// TestGroupByRegistrableDomain tests the GroupByRegistrableDomain and GroupByPublicSuffix functions.
+func TestGroupByRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name  string
+		group func() map[string][]DomainItem
+		want  map[string][]string
+	}{
+		{
+			name:  "registrable domain",
+			group: testDomains.GroupByRegistrableDomain,
+			want: map[string][]string{
+				"whois.co.uk":     {"whois.co.uk", "api.whois.co.uk"},
+				"whois.com":       {"whois.com", "login.whois.com"},
+				"whois.github.io": {"whois.github.io"},
+			},
+		},
+		{
+			name:  "public suffix",
+			group: testDomains.GroupByPublicSuffix,
+			want: map[string][]string{
+				"co.uk":     {"whois.co.uk", "api.whois.co.uk"},
+				"com":       {"whois.com", "login.whois.com"},
+				"github.io": {"whois.github.io"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string][]string)
+			for k, items := range tt.group() {
+				got[k] = domainNames(items)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFilterRegistrableDomain tests the FilterRegistrableDomain and FilterPublicSuffix functions.
+func TestFilterRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		got  *BrandAlertResponse
+		want []string
+	}{
+		{
+			name: "registrable domain",
+			got:  testDomains.FilterRegistrableDomain("www.whois.co.uk", "whois.github.io"),
+			want: []string{"api.whois.co.uk", "whois.co.uk", "whois.github.io"},
+		},
+		{
+			name: "public suffix",
+			got:  testDomains.FilterPublicSuffix(".COM"),
+			want: []string{"login.whois.com", "whois.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := domainNames(tt.got.DomainsList)
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) || tt.got.DomainsCount != len(tt.want) {
+				t.Errorf("got = %v (%d), want %v", got, tt.got.DomainsCount, tt.want)
+			}
+		})
+	}
+}
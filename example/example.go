@@ -5,7 +5,6 @@ import (
 	"errors"
 	brandalert "github.com/whois-api-llc/brand-alert-go"
 	"log"
-	"net/http"
 	"time"
 )
 
@@ -35,10 +34,10 @@ func BrandAlertPreview(apikey string) {
 
 func BrandAlertPurchase(apikey string) {
 	client := brandalert.NewClient(apikey, brandalert.ClientParams{
-		HTTPClient: &http.Client{
-			Transport: nil,
-			Timeout:   40 * time.Second,
-		},
+		ConnectTimeout: 10 * time.Second,
+		HeaderTimeout:  40 * time.Second,
+		// large purchase responses may take a while to download
+		BodyTimeout: 2 * time.Minute,
 	})
 
 	// Get parsed Brand Alert API response as a model instance.
@@ -63,11 +62,14 @@ func BrandAlertPurchase(apikey string) {
 		return
 	}
 
-	// Then print all "added" domains.
-	for _, obj := range brandAlertResp.DomainsList {
-		if obj.Action == brandalert.Added {
-			log.Println(obj.DomainName, obj.Action, time.Time(obj.Date).Format("2006-01-02"))
-		}
+	// Then print all "added" domains sorted by name.
+	for _, obj := range brandAlertResp.FilterAction(brandalert.Added).SortByName().DomainsList {
+		log.Println(obj.DomainName, obj.Action, time.Time(obj.Date).Format("2006-01-02"))
+	}
+
+	// Print the number of domains per action.
+	for action, count := range brandAlertResp.Summary().ByAction {
+		log.Println(action, count)
 	}
 
 	log.Println("raw response is always in JSON format. Most likely you don't need it.")
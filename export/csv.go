@@ -0,0 +1,212 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// CSVParams is used to create CSV writers and readers. None of parameters are
+// mandatory and leaving this struct empty works just fine for most cases.
+type CSVParams struct {
+	// Columns is the list of columns to write, or to expect if NoHeader is set.
+	// If it's empty then DefaultColumns is used.
+	Columns []Column
+
+	// NoHeader disables the header row. Readers then expect Columns in order.
+	NoHeader bool
+
+	// Comma is the field delimiter. If it's zero then ',' is used.
+	Comma rune
+}
+
+// columns returns the columns to use.
+func (p CSVParams) columns() []Column {
+	if len(p.Columns) == 0 {
+		return DefaultColumns
+	}
+	return p.Columns
+}
+
+// comma returns the field delimiter to use.
+func (p CSVParams) comma() rune {
+	if p.Comma == 0 {
+		return ','
+	}
+	return p.Comma
+}
+
+// validateColumns validates the columns.
+func validateColumns(columns []Column) error {
+	for _, c := range columns {
+		switch c {
+		case ColumnDomainName, ColumnAction, ColumnDate, ColumnTLD, ColumnRegistrableDomain, ColumnPublicSuffix:
+		default:
+			return fmt.Errorf(`unknown column "%s"`, c)
+		}
+	}
+
+	return nil
+}
+
+// CSVWriter writes domains as CSV records.
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []Column
+	header  bool
+	record  []string
+}
+
+var _ ItemWriter = &CSVWriter{}
+
+// NewCSVWriter creates CSVWriter with specified parameters.
+func NewCSVWriter(w io.Writer, params CSVParams) (*CSVWriter, error) {
+	columns := params.columns()
+	if err := validateColumns(columns); err != nil {
+		return nil, err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = params.comma()
+
+	return &CSVWriter{
+		w:       cw,
+		columns: columns,
+		header:  !params.NoHeader,
+		record:  make([]string, len(columns)),
+	}, nil
+}
+
+// writeHeader writes the header row once.
+func (w *CSVWriter) writeHeader() error {
+	if !w.header {
+		return nil
+	}
+	w.header = false
+
+	for i, c := range w.columns {
+		w.record[i] = string(c)
+	}
+
+	return w.w.Write(w.record)
+}
+
+// Write writes the domain as a CSV record. The header is written before the first record.
+func (w *CSVWriter) Write(item brandalert.DomainItem) error {
+	if err := w.writeHeader(); err != nil {
+		return fmt.Errorf("cannot write header: %w", err)
+	}
+
+	for i, c := range w.columns {
+		w.record[i] = value(item, c)
+	}
+
+	if err := w.w.Write(w.record); err != nil {
+		return fmt.Errorf("cannot write record: %w", err)
+	}
+
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer. The header is
+// written even if there were no records.
+func (w *CSVWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return fmt.Errorf("cannot write header: %w", err)
+	}
+
+	w.w.Flush()
+
+	return w.w.Error()
+}
+
+// CSVReader reads domains from CSV records.
+type CSVReader struct {
+	r       *csv.Reader
+	columns []Column
+	header  bool
+}
+
+var _ ItemReader = &CSVReader{}
+
+// NewCSVReader creates CSVReader with specified parameters. If the input has
+// a header, the columns are detected by it and unknown columns are skipped.
+func NewCSVReader(r io.Reader, params CSVParams) (*CSVReader, error) {
+	columns := params.columns()
+	if err := validateColumns(columns); err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = params.comma()
+	cr.ReuseRecord = true
+	if params.NoHeader {
+		cr.FieldsPerRecord = len(columns)
+	}
+
+	return &CSVReader{
+		r:       cr,
+		columns: columns,
+		header:  !params.NoHeader,
+	}, nil
+}
+
+// readHeader reads the header row once and detects the columns.
+func (r *CSVReader) readHeader() error {
+	if !r.header {
+		return nil
+	}
+	r.header = false
+
+	record, err := r.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return fmt.Errorf("cannot read header: %w", err)
+	}
+
+	r.columns = make([]Column, len(record))
+
+	found := false
+	for i, name := range record {
+		r.columns[i] = Column(name)
+		if r.columns[i] == ColumnDomainName {
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf(`cannot read header: no "%s" column`, ColumnDomainName)
+	}
+
+	r.r.FieldsPerRecord = len(record)
+
+	return nil
+}
+
+// Read reads the next domain. It returns io.EOF at the end of input.
+func (r *CSVReader) Read() (item brandalert.DomainItem, err error) {
+	if err = r.readHeader(); err != nil {
+		return item, err
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return item, err
+		}
+		return item, fmt.Errorf("cannot read record: %w", err)
+	}
+
+	for i, c := range r.columns {
+		if err := setValue(&item, c, record[i]); err != nil {
+			line, _ := r.r.FieldPos(i)
+			return item, fmt.Errorf("cannot read record: line %d: %s: %w", line, c, err)
+		}
+	}
+
+	return item, nil
+}
@@ -0,0 +1,127 @@
+// Package export writes and reads Brand Alert API domains as flat files:
+// CSV and JSON Lines. Writers and readers are streaming, so large results
+// can be archived and re-imported without keeping them in memory.
+package export
+
+import (
+	"io"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// Column is the column of the exported domain.
+type Column string
+
+// List of possible columns.
+const (
+	// ColumnDomainName is the full domain name.
+	ColumnDomainName Column = "domainName"
+
+	// ColumnAction is the related action.
+	ColumnAction Column = "action"
+
+	// ColumnDate is the event date.
+	ColumnDate Column = "date"
+
+	// ColumnTLD is the last label of the domain name. It is ignored by readers.
+	ColumnTLD Column = "tld"
+
+	// ColumnRegistrableDomain is the registrable domain. It is ignored by readers.
+	ColumnRegistrableDomain Column = "registrableDomain"
+
+	// ColumnPublicSuffix is the public suffix. It is ignored by readers.
+	ColumnPublicSuffix Column = "publicSuffix"
+)
+
+var _ = []Column{
+	ColumnDomainName,
+	ColumnAction,
+	ColumnDate,
+	ColumnTLD,
+	ColumnRegistrableDomain,
+	ColumnPublicSuffix,
+}
+
+// DefaultColumns is the list of columns used if none specified.
+var DefaultColumns = []Column{
+	ColumnDomainName,
+	ColumnAction,
+	ColumnDate,
+}
+
+// ItemWriter is the interface implemented by all writers.
+type ItemWriter interface {
+	// Write writes the domain.
+	Write(item brandalert.DomainItem) error
+
+	// Flush writes any buffered data to the underlying io.Writer.
+	Flush() error
+}
+
+// ItemReader is the interface implemented by all readers.
+type ItemReader interface {
+	// Read reads the next domain. It returns io.EOF at the end of input.
+	Read() (brandalert.DomainItem, error)
+}
+
+// WriteAll writes all items and flushes the writer.
+func WriteAll(w ItemWriter, items []brandalert.DomainItem) error {
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// ReadAll reads all remaining items.
+func ReadAll(r ItemReader) ([]brandalert.DomainItem, error) {
+	var items []brandalert.DomainItem
+
+	for {
+		item, err := r.Read()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return items, err
+		}
+
+		items = append(items, item)
+	}
+}
+
+// value returns the column value of the domain.
+func value(item brandalert.DomainItem, column Column) string {
+	switch column {
+	case ColumnDomainName:
+		return item.DomainName
+	case ColumnAction:
+		return item.Action.String()
+	case ColumnDate:
+		return item.Date.String()
+	case ColumnTLD:
+		return item.TLD()
+	case ColumnRegistrableDomain:
+		return item.RegistrableDomain()
+	case ColumnPublicSuffix:
+		return item.PublicSuffix()
+	}
+
+	return ""
+}
+
+// setValue sets the column value of the domain. Computed columns are ignored.
+func setValue(item *brandalert.DomainItem, column Column, v string) error {
+	switch column {
+	case ColumnDomainName:
+		item.DomainName = v
+	case ColumnAction:
+		item.Action, _ = brandalert.ParseAction(v)
+	case ColumnDate:
+		return item.Date.UnmarshalText([]byte(v))
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// testItems returns the domains for testing.
+func testItems(t *testing.T) []brandalert.DomainItem {
+	var resp brandalert.BrandAlertResponse
+
+	err := json.Unmarshal([]byte(`{"domainsCount":3,"domainsList":[
+{"domainName":"batchwhois.co.uk","date":"2022-10-30","action":"discovered"},
+{"domainName":"whois, inc.com","date":"2022-10-29","action":"dropped"},
+{"domainName":"whoisdodster.com","date":"","action":"added"}]}`), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.DomainsList
+}
+
+// TestCSV tests the CSV writer and reader.
+func TestCSV(t *testing.T) {
+	items := testItems(t)
+
+	tests := []struct {
+		name   string
+		params CSVParams
+		want   string
+	}{
+		{
+			name: "default",
+			want: `domainName,action,date
+batchwhois.co.uk,discovered,2022-10-30
+"whois, inc.com",dropped,2022-10-29
+whoisdodster.com,added,
+`,
+		},
+		{
+			name: "custom",
+			params: CSVParams{
+				Columns:  []Column{ColumnDate, ColumnDomainName, ColumnRegistrableDomain, ColumnPublicSuffix, ColumnTLD},
+				NoHeader: true,
+				Comma:    ';',
+			},
+			want: `2022-10-30;batchwhois.co.uk;batchwhois.co.uk;co.uk;uk
+2022-10-29;whois, inc.com;whois, inc.com;com;com
+;whoisdodster.com;whoisdodster.com;com;com
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			w, err := NewCSVWriter(&b, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := WriteAll(w, items); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("CSVWriter got = %v, want %v", b.String(), tt.want)
+			}
+
+			r, err := NewCSVReader(&b, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := items
+			if tt.params.NoHeader {
+				want = make([]brandalert.DomainItem, 0, len(items))
+				for _, item := range items {
+					want = append(want, brandalert.DomainItem{DomainName: item.DomainName, Date: item.Date})
+				}
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("CSVReader got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestCSVReaderErrors tests the CSV reader errors.
+func TestCSVReaderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		params  CSVParams
+		wantErr string
+	}{
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: "EOF",
+		},
+		{
+			name:    "no domain name",
+			input:   "action,date\nadded,2022-10-30\n",
+			wantErr: `cannot read header: no "domainName" column`,
+		},
+		{
+			name:    "invalid date",
+			input:   "domainName,comment,date\nwhois.com,ok,2022-10-30\nwhois.net,bad,30.10.2022\n",
+			wantErr: `cannot read record: line 3: date: parsing time "30.10.2022" as "2006-01-02": cannot parse "30.10.2022" as "2006"`,
+		},
+		{
+			name:    "wrong number of fields",
+			input:   "whois.com,added\n",
+			params:  CSVParams{NoHeader: true},
+			wantErr: "cannot read record: record on line 1: wrong number of fields",
+		},
+		{
+			name:    "unknown column",
+			params:  CSVParams{Columns: []Column{"registrar"}},
+			wantErr: `unknown column "registrar"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewCSVReader(strings.NewReader(tt.input), tt.params)
+			if err == nil {
+				_, err = ReadAll(r)
+				if err == nil {
+					_, err = r.Read()
+				}
+			}
+
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestJSONL tests the JSON Lines writer and reader.
+func TestJSONL(t *testing.T) {
+	items := testItems(t)
+
+	var b bytes.Buffer
+
+	if err := WriteAll(NewJSONLWriter(&b), items); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `{"domainName":"batchwhois.co.uk","action":"discovered","date":"2022-10-30"}
+{"domainName":"whois, inc.com","action":"dropped","date":"2022-10-29"}
+{"domainName":"whoisdodster.com","action":"added","date":""}
+`
+	if b.String() != want {
+		t.Errorf("JSONLWriter got = %v, want %v", b.String(), want)
+	}
+
+	got, err := ReadAll(NewJSONLReader(strings.NewReader("\n" + b.String() + "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, items) {
+		t.Errorf("JSONLReader got = %v, want %v", got, items)
+	}
+
+	r := NewJSONLReader(strings.NewReader(`{"domainName":"whois.com"}` + "\n{\n"))
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.Read()
+	if err == nil || err.Error() != "cannot read record: line 2: unexpected end of JSON input" {
+		t.Errorf("JSONLReader error = %v", err)
+	}
+
+	if _, err = NewJSONLReader(strings.NewReader("")).Read(); err != io.EOF {
+		t.Errorf("JSONLReader error = %v, want EOF", err)
+	}
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// maxLineSize is the maximum size of the JSON Lines line.
+const maxLineSize = 1 << 20
+
+// JSONLWriter writes domains as JSON Lines: one JSON object per line.
+type JSONLWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+var _ ItemWriter = &JSONLWriter{}
+
+// NewJSONLWriter creates JSONLWriter.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	bw := bufio.NewWriter(w)
+
+	return &JSONLWriter{
+		w:   bw,
+		enc: json.NewEncoder(bw),
+	}
+}
+
+// Write writes the domain as a JSON line.
+func (w *JSONLWriter) Write(item brandalert.DomainItem) error {
+	if err := w.enc.Encode(item); err != nil {
+		return fmt.Errorf("cannot write record: %w", err)
+	}
+
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *JSONLWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// JSONLReader reads domains from JSON Lines. Empty lines are skipped.
+type JSONLReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+var _ ItemReader = &JSONLReader{}
+
+// NewJSONLReader creates JSONLReader.
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	return &JSONLReader{
+		s: s,
+	}
+}
+
+// Read reads the next domain. It returns io.EOF at the end of input.
+func (r *JSONLReader) Read() (item brandalert.DomainItem, err error) {
+	for r.s.Scan() {
+		r.line++
+
+		line := bytes.TrimSpace(r.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(line, &item); err != nil {
+			return item, fmt.Errorf("cannot read record: line %d: %w", r.line, err)
+		}
+
+		return item, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return item, fmt.Errorf("cannot read record: line %d: %w", r.line+1, err)
+	}
+
+	return item, io.EOF
+}
@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// List of error codes returned by the gateway.
+const (
+	CodeUnauthorized     = "unauthorized"
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidArgument  = "invalid_argument"
+	CodeQuotaExceeded    = "quota_exceeded"
+	CodeCreditsExhausted = "credits_exhausted"
+	CodeRateLimited      = "rate_limited"
+	CodeUpstreamError    = "upstream_error"
+	CodeTimeout          = "timeout"
+)
+
+// Error is the JSON error returned by the gateway.
+type Error struct {
+	// Status is the HTTP status code.
+	Status int `json:"-"`
+
+	// Code is the error code, one of the Code constants.
+	Code string `json:"code"`
+
+	// Message is the error description.
+	Message string `json:"message"`
+
+	// Argument is the invalid argument name of CodeInvalidArgument errors.
+	Argument string `json:"argument,omitempty"`
+
+	// UpstreamCode is the Brand Alert API error code or HTTP status code.
+	UpstreamCode int `json:"upstreamCode,omitempty"`
+}
+
+// Error returns error message as a string.
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// upstreamStatus maps the Brand Alert API status code to the gateway status
+// code. Errors of the gateway API key are not the caller's fault, so they are
+// reported as bad gateway, except the exhausted credits reported as payment
+// required, so that callers can tell them from the upstream failures.
+func upstreamStatus(code int) (int, string) {
+	switch {
+	case code == http.StatusPaymentRequired:
+		return http.StatusPaymentRequired, CodeCreditsExhausted
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+		return http.StatusBadRequest, CodeInvalidArgument
+	case code == http.StatusTooManyRequests:
+		return http.StatusTooManyRequests, CodeRateLimited
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return http.StatusGatewayTimeout, CodeTimeout
+	}
+
+	return http.StatusBadGateway, CodeUpstreamError
+}
+
+// mapError converts the client error to the gateway error.
+func mapError(err error) *Error {
+	var (
+		gwErr   *Error
+		argErr  *brandalert.ArgError
+		msgErr  *brandalert.ErrorMessage
+		respErr *brandalert.ErrorResponse
+	)
+
+	switch {
+	case errors.As(err, &gwErr):
+		return gwErr
+	case errors.As(err, &argErr):
+		return &Error{
+			Status:   http.StatusBadRequest,
+			Code:     CodeInvalidArgument,
+			Message:  argErr.Error(),
+			Argument: argErr.Name,
+		}
+	case errors.As(err, &msgErr):
+		status, code := upstreamStatus(msgErr.Code)
+		return &Error{
+			Status:       status,
+			Code:         code,
+			Message:      strings.Join(msgErr.Message, "; "),
+			UpstreamCode: msgErr.Code,
+		}
+	case errors.As(err, &respErr):
+		status, code := upstreamStatus(respErr.Response.StatusCode)
+		return &Error{
+			Status:       status,
+			Code:         code,
+			Message:      respErr.Error(),
+			UpstreamCode: respErr.Response.StatusCode,
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Status: http.StatusGatewayTimeout, Code: CodeTimeout, Message: "upstream request timed out"}
+	}
+
+	return &Error{Status: http.StatusBadGateway, Code: CodeUpstreamError, Message: err.Error()}
+}
+
+// writeError writes the JSON error response.
+func writeError(w http.ResponseWriter, e *Error) {
+	if e.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="brand-alert"`)
+	}
+
+	writeJSON(w, e.Status, struct {
+		Error *Error `json:"error"`
+	}{e})
+}
+
+// writeJSON writes the JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)+1))
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+}
@@ -0,0 +1,352 @@
+// Package gateway is the HTTP server fronting the Brand Alert API client for
+// internal services. Callers authenticate with their own bearer tokens and
+// spend their own credit quotas, while the API key stays in the gateway.
+//
+// Endpoints:
+//
+//	POST /v1/preview   returns {"domainsCount": N}, no credits spent
+//	POST /v1/purchase  returns BrandAlertResponse JSON
+//	POST /v1/raw       returns the raw Brand Alert API response
+//	GET  /v1/quota     returns the caller Usage
+//
+// Query endpoints accept Request JSON. Errors are returned as
+// {"error": Error} with the HTTP status matching the error code.
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+const (
+	// defaultPurchaseCredits is the default number of credits of purchase and raw queries.
+	defaultPurchaseCredits = 1
+
+	// defaultMaxBodySize is the default limit of the request body size.
+	defaultMaxBodySize = 64 << 10
+
+	// dateFormat is the format of the sinceDate field.
+	dateFormat = "2006-01-02"
+)
+
+// Caller is the internal service allowed to use the gateway.
+type Caller struct {
+	// Name is the caller name used in quotas and logs.
+	Name string
+
+	// Token is the bearer token of the caller.
+	Token string
+
+	// Quota is the caller credit quota.
+	Quota Quota
+}
+
+// Params is used to create Server.
+type Params struct {
+	// Client is the Brand Alert API client holding the API key.
+	Client brandalert.BrandAlert
+
+	// Callers is the list of allowed callers.
+	Callers []Caller
+
+	// PurchaseCredits is the number of credits spent by purchase and raw queries. Default: 1.
+	PurchaseCredits int
+
+	// MaxBodySize is the limit of the request body size in bytes. Default: 64 KiB.
+	MaxBodySize int64
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+// Server is the HTTP handler of the gateway.
+type Server struct {
+	params  Params
+	callers []Caller
+	quotas  quotas
+	mux     *http.ServeMux
+}
+
+// New creates Server. Callers must have unique names and non-empty tokens.
+func New(params Params) (*Server, error) {
+	if params.Client == nil {
+		return nil, errors.New("client is required")
+	}
+
+	names := make(map[string]bool, len(params.Callers))
+	tokens := make(map[string]bool, len(params.Callers))
+
+	for i, c := range params.Callers {
+		switch {
+		case c.Name == "":
+			return nil, fmt.Errorf("caller #%d: name can not be empty", i+1)
+		case c.Token == "":
+			return nil, fmt.Errorf("caller %q: token can not be empty", c.Name)
+		case names[c.Name]:
+			return nil, fmt.Errorf("caller %q: duplicate name", c.Name)
+		case tokens[c.Token]:
+			return nil, fmt.Errorf("caller %q: duplicate token", c.Name)
+		case c.Quota.Credits < 0 || c.Quota.Period < 0:
+			return nil, fmt.Errorf("caller %q: quota can not be negative", c.Name)
+		}
+		names[c.Name], tokens[c.Token] = true, true
+	}
+
+	if params.PurchaseCredits <= 0 {
+		params.PurchaseCredits = defaultPurchaseCredits
+	}
+	if params.MaxBodySize <= 0 {
+		params.MaxBodySize = defaultMaxBodySize
+	}
+
+	s := &Server{
+		params:  params,
+		callers: append([]Caller(nil), params.Callers...),
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.Handle("/v1/preview", s.query(0, s.preview))
+	s.mux.Handle("/v1/purchase", s.query(params.PurchaseCredits, s.purchase))
+	s.mux.Handle("/v1/raw", s.query(params.PurchaseCredits, s.raw))
+	s.mux.HandleFunc("/v1/quota", s.quota)
+
+	return s, nil
+}
+
+// ServeHTTP serves the gateway endpoints.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// now returns the current time.
+func (s *Server) now() time.Time {
+	if s.params.Now != nil {
+		return s.params.Now()
+	}
+	return time.Now()
+}
+
+// Request is the query request of the gateway endpoints.
+type Request struct {
+	// IncludeSearchTerms is the list of 1 to 4 terms present in the domain names.
+	IncludeSearchTerms brandalert.SearchTerms `json:"includeSearchTerms"`
+
+	// ExcludeSearchTerms is the list of up to 4 terms absent in the domain names.
+	ExcludeSearchTerms brandalert.SearchTerms `json:"excludeSearchTerms,omitempty"`
+
+	// SinceDate is the date in the YYYY-MM-DD format to search activities since.
+	SinceDate string `json:"sinceDate,omitempty"`
+
+	// WithTypos enriches the search terms with their typos.
+	WithTypos bool `json:"withTypos,omitempty"`
+
+	// Punycode encodes the domain names to Punycode. Default: true.
+	Punycode *bool `json:"punycode,omitempty"`
+
+	// ResponseFormat is the format of raw responses: json or xml. Default: json.
+	ResponseFormat string `json:"responseFormat,omitempty"`
+}
+
+// Options returns the client options of the request.
+func (req *Request) Options() ([]brandalert.Option, error) {
+	opts := []brandalert.Option{brandalert.OptionWithTypos(req.WithTypos)}
+
+	if req.SinceDate != "" {
+		date, err := time.Parse(dateFormat, req.SinceDate)
+		if err != nil {
+			return nil, &brandalert.ArgError{Name: "sinceDate", Message: "must be a date in the YYYY-MM-DD format."}
+		}
+		opts = append(opts, brandalert.OptionSinceDate(date))
+	}
+
+	if req.Punycode != nil {
+		opts = append(opts, brandalert.OptionPunycode(*req.Punycode))
+	}
+
+	switch strings.ToLower(req.ResponseFormat) {
+	case "":
+	case "json", "xml":
+		opts = append(opts, brandalert.OptionResponseFormat(strings.ToLower(req.ResponseFormat)))
+	default:
+		return nil, &brandalert.ArgError{Name: "responseFormat", Message: "must be json or xml."}
+	}
+
+	return opts, nil
+}
+
+// queryFunc runs the query and writes its response.
+type queryFunc func(w http.ResponseWriter, r *http.Request, req *Request, opts []brandalert.Option) error
+
+// query returns the handler authenticating the caller, decoding the request
+// and spending the credits of successful queries.
+func (s *Server) query(credits int, fn queryFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, &Error{Status: http.StatusMethodNotAllowed, Code: CodeInvalidRequest, Message: "method must be POST"})
+			return
+		}
+
+		caller := s.authenticate(r)
+		if caller == nil {
+			writeError(w, &Error{Status: http.StatusUnauthorized, Code: CodeUnauthorized, Message: "missing or invalid bearer token"})
+			return
+		}
+
+		req, err := s.decode(w, r)
+		if err != nil {
+			writeError(w, mapError(err))
+			return
+		}
+
+		if err := brandalert.ValidateSearchTerms(req.IncludeSearchTerms.OrNil(), req.ExcludeSearchTerms.OrNil()); err != nil {
+			writeError(w, mapError(err))
+			return
+		}
+
+		opts, err := req.Options()
+		if err != nil {
+			writeError(w, mapError(err))
+			return
+		}
+
+		usage, ok := s.quotas.reserve(caller, credits, s.now())
+		setUsageHeaders(w, usage)
+		if !ok {
+			if usage.Reset != nil {
+				w.Header().Set("Retry-After", strconv.Itoa(int(usage.Reset.Sub(s.now()).Seconds())+1))
+			}
+			writeError(w, &Error{
+				Status:  http.StatusTooManyRequests,
+				Code:    CodeQuotaExceeded,
+				Message: fmt.Sprintf("quota of %d credits exceeded", usage.Limit),
+			})
+			return
+		}
+
+		if err := fn(w, r, req, opts); err != nil {
+			s.quotas.refund(caller, credits, s.now())
+			setUsageHeaders(w, s.quotas.usage(caller, s.now()))
+			writeError(w, mapError(err))
+		}
+	})
+}
+
+// authenticate returns the caller of the bearer token.
+func (s *Server) authenticate(r *http.Request) *Caller {
+	const prefix = "bearer "
+
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return nil
+	}
+
+	token := []byte(strings.TrimSpace(auth[len(prefix):]))
+
+	for i := range s.callers {
+		if subtle.ConstantTimeCompare(token, []byte(s.callers[i].Token)) == 1 {
+			return &s.callers[i]
+		}
+	}
+
+	return nil
+}
+
+// decode decodes the request body. Unknown fields, including apiKey, are rejected.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request) (*Request, error) {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.params.MaxBodySize))
+	dec.DisallowUnknownFields()
+
+	var req Request
+	if err := dec.Decode(&req); err != nil {
+		return nil, &Error{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Message: "cannot decode request: " + err.Error()}
+	}
+
+	return &req, nil
+}
+
+// setUsageHeaders sets the quota headers of the response.
+func setUsageHeaders(w http.ResponseWriter, u Usage) {
+	if u.Limit == 0 {
+		return
+	}
+
+	w.Header().Set("X-Quota-Limit", strconv.Itoa(u.Limit))
+	w.Header().Set("X-Quota-Remaining", strconv.Itoa(u.Remaining))
+	if u.Reset != nil {
+		w.Header().Set("X-Quota-Reset", strconv.FormatInt(u.Reset.Unix(), 10))
+	}
+}
+
+// preview serves the preview query.
+func (s *Server) preview(w http.ResponseWriter, r *http.Request, req *Request, opts []brandalert.Option) error {
+	count, _, err := s.params.Client.Preview(r.Context(), req.IncludeSearchTerms.OrNil(), req.ExcludeSearchTerms.OrNil(), opts...)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		DomainsCount int `json:"domainsCount"`
+	}{count})
+
+	return nil
+}
+
+// purchase serves the purchase query.
+func (s *Server) purchase(w http.ResponseWriter, r *http.Request, req *Request, opts []brandalert.Option) error {
+	resp, _, err := s.params.Client.Purchase(r.Context(), req.IncludeSearchTerms.OrNil(), req.ExcludeSearchTerms.OrNil(), opts...)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+
+	return nil
+}
+
+// raw serves the raw query passing the upstream body through.
+func (s *Server) raw(w http.ResponseWriter, r *http.Request, req *Request, opts []brandalert.Option) error {
+	resp, err := s.params.Client.RawData(r.Context(), req.IncludeSearchTerms.OrNil(), req.ExcludeSearchTerms.OrNil(), opts...)
+	if err != nil {
+		return err
+	}
+
+	contentType := "application/json"
+	if resp.Response != nil && resp.Header.Get("Content-Type") != "" {
+		contentType = resp.Header.Get("Content-Type")
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(resp.Body)
+
+	return nil
+}
+
+// quota serves the caller usage.
+func (s *Server) quota(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, &Error{Status: http.StatusMethodNotAllowed, Code: CodeInvalidRequest, Message: "method must be GET"})
+		return
+	}
+
+	caller := s.authenticate(r)
+	if caller == nil {
+		writeError(w, &Error{Status: http.StatusUnauthorized, Code: CodeUnauthorized, Message: "missing or invalid bearer token"})
+		return
+	}
+
+	usage := s.quotas.usage(caller, s.now())
+	setUsageHeaders(w, usage)
+	writeJSON(w, http.StatusOK, usage)
+}
@@ -0,0 +1,332 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// upstream is the Brand Alert API stub recording the API keys of requests.
+type upstream struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	keys []string
+
+	// nullExcludes is the number of requests with null exclude terms.
+	nullExcludes int
+}
+
+func newUpstream() *upstream {
+	u := &upstream{}
+
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			APIKey             string          `json:"apiKey"`
+			IncludeSearchTerms []string        `json:"includeSearchTerms"`
+			ExcludeSearchTerms json.RawMessage `json:"excludeSearchTerms"`
+			Mode               string          `json:"mode"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		u.mu.Lock()
+		u.keys = append(u.keys, req.APIKey)
+		if string(req.ExcludeSearchTerms) == "null" {
+			u.nullExcludes++
+		}
+		u.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.IncludeSearchTerms[0] {
+		case "denied":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"code":403,"messages":"Access restricted. Check credits balance or enter the correct API key."}`)
+		case "exhausted":
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = io.WriteString(w, `{"code":402,"messages":"Access restricted. Check credits balance."}`)
+		case "invalid":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = io.WriteString(w, `{"code":422,"messages":["sinceDate is out of range"]}`)
+		case "busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = io.WriteString(w, `{"domainsCount":1,"domainsList":[{"domainName":"whois-login.com","date":"2022-10-30","action":"added"}]}`)
+		}
+	}))
+
+	return u
+}
+
+// newServer creates the gateway in front of the upstream stub.
+func newServer(t *testing.T, u *upstream, now *time.Time) *Server {
+	base, _ := url.Parse(u.URL)
+
+	s, err := New(Params{
+		Client: brandalert.NewClient("secret-api-key", brandalert.ClientParams{BrandAlertBaseURL: base}),
+		Callers: []Caller{
+			{Name: "soc", Token: "soc-token", Quota: Quota{Credits: 2, Period: time.Hour}},
+			{Name: "legal", Token: "legal-token"},
+		},
+		Now: func() time.Time { return *now },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// call sends the request to the gateway and returns the status and body.
+func call(s *Server, method, path, token, body string) (*http.Response, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	return rec.Result(), strings.TrimSpace(rec.Body.String())
+}
+
+// TestServer tests the gateway endpoints and error mapping.
+func TestServer(t *testing.T) {
+	u := newUpstream()
+	defer u.Close()
+
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	s := newServer(t, u, &now)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "preview",
+			method:     http.MethodPost,
+			path:       "/v1/preview",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["whois"],"sinceDate":"2022-10-30"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"domainsCount":1}`,
+		},
+		{
+			name:       "purchase",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["whois"],"excludeSearchTerms":["api"],"withTypos":true}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"domainsList":[{"domainName":"whois-login.com","action":"added","date":"2022-10-30"}],"domainsCount":1}`,
+		},
+		{
+			name:       "raw",
+			method:     http.MethodPost,
+			path:       "/v1/raw",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["whois"]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"domainsCount":1,"domainsList":[{"domainName":"whois-login.com","date":"2022-10-30","action":"added"}]}`,
+		},
+		{
+			name:       "no token",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			body:       `{"includeSearchTerms":["whois"]}`,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":{"code":"unauthorized","message":"missing or invalid bearer token"}}`,
+		},
+		{
+			name:       "wrong token",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			token:      "secret-api-key",
+			body:       `{"includeSearchTerms":["whois"]}`,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":{"code":"unauthorized","message":"missing or invalid bearer token"}}`,
+		},
+		{
+			name:       "method",
+			method:     http.MethodGet,
+			path:       "/v1/purchase",
+			token:      "legal-token",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   `{"error":{"code":"invalid_request","message":"method must be POST"}}`,
+		},
+		{
+			name:       "api key in request",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			token:      "legal-token",
+			body:       `{"apiKey":"other","includeSearchTerms":["whois"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"invalid_request","message":"cannot decode request: json: unknown field \"apiKey\""}}`,
+		},
+		{
+			name:       "arg error",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":[]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"error":{"code":"invalid_argument","message":"invalid argument: \"includeSearchTerms\" must have between 1 and 4 items.",` +
+				`"argument":"includeSearchTerms"}}`,
+		},
+		{
+			name:       "since date",
+			method:     http.MethodPost,
+			path:       "/v1/preview",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["whois"],"sinceDate":"30.10.2022"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"error":{"code":"invalid_argument","message":"invalid argument: \"sinceDate\" must be a date in the YYYY-MM-DD format.",` +
+				`"argument":"sinceDate"}}`,
+		},
+		{
+			name:       "error message",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["denied"]}`,
+			wantStatus: http.StatusBadGateway,
+			wantBody: `{"error":{"code":"upstream_error","message":"Access restricted. Check credits balance or enter the correct API key.",` +
+				`"upstreamCode":403}}`,
+		},
+		{
+			name:       "credits exhausted",
+			method:     http.MethodPost,
+			path:       "/v1/purchase",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["exhausted"]}`,
+			wantStatus: http.StatusPaymentRequired,
+			wantBody: `{"error":{"code":"credits_exhausted","message":"Access restricted. Check credits balance.",` +
+				`"upstreamCode":402}}`,
+		},
+		{
+			name:       "invalid upstream argument",
+			method:     http.MethodPost,
+			path:       "/v1/preview",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["invalid"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"invalid_argument","message":"sinceDate is out of range","upstreamCode":422}}`,
+		},
+		{
+			name:       "error response",
+			method:     http.MethodPost,
+			path:       "/v1/raw",
+			token:      "legal-token",
+			body:       `{"includeSearchTerms":["busy"]}`,
+			wantStatus: http.StatusTooManyRequests,
+			wantBody:   `{"error":{"code":"rate_limited","message":"API failed with status code: 429","upstreamCode":429}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := call(s, tt.method, tt.path, tt.token, tt.body)
+			if resp.StatusCode != tt.wantStatus || body != tt.wantBody {
+				t.Errorf("got = %v %s, want %v %s", resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+			if resp.StatusCode == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Error("got no WWW-Authenticate header")
+			}
+		})
+	}
+
+	for _, key := range u.keys {
+		if key != "secret-api-key" {
+			t.Errorf("got upstream API key = %v", key)
+		}
+	}
+
+	if u.nullExcludes != 0 {
+		t.Errorf("got %d upstream requests with null excludeSearchTerms", u.nullExcludes)
+	}
+}
+
+// TestQuota tests spending, refunding and resetting the caller credits.
+func TestQuota(t *testing.T) {
+	u := newUpstream()
+	defer u.Close()
+
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	s := newServer(t, u, &now)
+
+	steps := []struct {
+		path       string
+		term       string
+		advance    time.Duration
+		wantStatus int
+		wantLeft   string
+	}{
+		{path: "/v1/purchase", term: "whois", wantStatus: http.StatusOK, wantLeft: "1"},
+		{path: "/v1/purchase", term: "denied", wantStatus: http.StatusBadGateway, wantLeft: "1"},
+		{path: "/v1/preview", term: "whois", wantStatus: http.StatusOK, wantLeft: "1"},
+		{path: "/v1/raw", term: "whois", wantStatus: http.StatusOK, wantLeft: "0"},
+		{path: "/v1/purchase", term: "whois", advance: 30 * time.Minute, wantStatus: http.StatusTooManyRequests, wantLeft: "0"},
+		{path: "/v1/preview", term: "whois", wantStatus: http.StatusOK, wantLeft: "0"},
+		{path: "/v1/purchase", term: "whois", advance: 30 * time.Minute, wantStatus: http.StatusOK, wantLeft: "1"},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+
+		resp, body := call(s, http.MethodPost, step.path, "soc-token", `{"includeSearchTerms":["`+step.term+`"]}`)
+		if resp.StatusCode != step.wantStatus || resp.Header.Get("X-Quota-Remaining") != step.wantLeft {
+			t.Errorf("step %d got = %v %s, remaining %v", i, resp.StatusCode, body, resp.Header.Get("X-Quota-Remaining"))
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") != "1801" {
+			t.Errorf("step %d got Retry-After = %v", i, resp.Header.Get("Retry-After"))
+		}
+	}
+
+	resp, body := call(s, http.MethodGet, "/v1/quota", "soc-token", "")
+	if want := `{"caller":"soc","limit":2,"used":1,"remaining":1,"reset":"2022-11-01T02:00:00Z"}`; resp.StatusCode != http.StatusOK || body != want {
+		t.Errorf("/v1/quota got = %v %s, want %s", resp.StatusCode, body, want)
+	}
+
+	if _, body := call(s, http.MethodGet, "/v1/quota", "legal-token", ""); body != `{"caller":"legal","limit":0,"used":0,"remaining":-1}` {
+		t.Errorf("/v1/quota got = %s", body)
+	}
+}
+
+// TestNew tests the caller validation.
+func TestNew(t *testing.T) {
+	client := brandalert.NewBasicClient("key")
+
+	tests := []struct {
+		name    string
+		callers []Caller
+		wantErr string
+	}{
+		{name: "valid", callers: []Caller{{Name: "a", Token: "1"}, {Name: "b", Token: "2"}}},
+		{name: "no name", callers: []Caller{{Token: "1"}}, wantErr: "caller #1: name can not be empty"},
+		{name: "no token", callers: []Caller{{Name: "a"}}, wantErr: `caller "a": token can not be empty`},
+		{name: "duplicate token", callers: []Caller{{Name: "a", Token: "1"}, {Name: "b", Token: "1"}}, wantErr: `caller "b": duplicate token`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(Params{Client: client, Callers: tt.callers})
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
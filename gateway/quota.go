@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+// Quota is the caller credit quota.
+type Quota struct {
+	// Credits is the number of credits the caller can spend per period.
+	// Zero means unlimited.
+	Credits int
+
+	// Period is the length of the quota window. The window starts with the
+	// first spent credit. Zero means the quota is never reset.
+	Period time.Duration
+}
+
+// Usage is the caller credit usage in the current quota window.
+type Usage struct {
+	// Caller is the caller name.
+	Caller string `json:"caller"`
+
+	// Limit is the number of credits per window, zero if unlimited.
+	Limit int `json:"limit"`
+
+	// Used is the number of credits spent in the window.
+	Used int `json:"used"`
+
+	// Remaining is the number of credits left in the window, -1 if unlimited.
+	Remaining int `json:"remaining"`
+
+	// Reset is the end of the window, nil if it never ends.
+	Reset *time.Time `json:"reset,omitempty"`
+}
+
+// window is the caller usage in the quota window.
+type window struct {
+	start time.Time
+	used  int
+}
+
+// quotas tracks the credit usage of callers.
+type quotas struct {
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+// current returns the current window of the caller starting a new one if
+// the previous has ended.
+func (q *quotas) current(c *Caller, now time.Time) *window {
+	if q.windows == nil {
+		q.windows = make(map[string]*window)
+	}
+
+	w, ok := q.windows[c.Name]
+	if !ok || (c.Quota.Period > 0 && !now.Before(w.start.Add(c.Quota.Period))) {
+		w = &window{start: now}
+		q.windows[c.Name] = w
+	}
+
+	return w
+}
+
+// reserve spends the credits if the caller quota allows it.
+func (q *quotas) reserve(c *Caller, credits int, now time.Time) (Usage, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w := q.current(c, now)
+
+	if c.Quota.Credits > 0 && w.used+credits > c.Quota.Credits {
+		return usage(c, w), false
+	}
+
+	w.used += credits
+
+	return usage(c, w), true
+}
+
+// refund returns the credits of the failed request.
+func (q *quotas) refund(c *Caller, credits int, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w := q.current(c, now)
+
+	w.used -= credits
+	if w.used < 0 {
+		w.used = 0
+	}
+}
+
+// usage returns the current usage of the caller.
+func (q *quotas) usage(c *Caller, now time.Time) Usage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return usage(c, q.current(c, now))
+}
+
+// usage returns the usage of the window.
+func usage(c *Caller, w *window) Usage {
+	u := Usage{Caller: c.Name, Limit: c.Quota.Credits, Used: w.used, Remaining: -1}
+
+	if c.Quota.Credits > 0 {
+		u.Remaining = c.Quota.Credits - w.used
+	}
+
+	if c.Quota.Period > 0 {
+		reset := w.start.Add(c.Quota.Period)
+		u.Reset = &reset
+	}
+
+	return u
+}
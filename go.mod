@@ -1,3 +1,10 @@
 module github.com/whois-api-llc/brand-alert-go
 
 go 1.17
+
+require (
+	go.etcd.io/bbolt v1.3.7
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.4.0 // indirect
@@ -0,0 +1,102 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bucket names.
+var (
+	metaBucket         = []byte("meta")
+	observationsBucket = []byte("observations")
+	domainsBucket      = []byte("domains")
+)
+
+// schemaKey is the meta key of the schema version.
+var schemaKey = []byte("schema")
+
+// migration upgrades the schema to the next version.
+type migration func(tx *bolt.Tx) error
+
+// migrations is the list of schema migrations. The schema version is the
+// number of applied migrations. New migrations are appended to the list.
+var migrations = []migration{
+	// 1: observations keyed by brand and domain.
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(observationsBucket)
+		return err
+	},
+
+	// 2: index of brands by domain.
+	func(tx *bolt.Tx) error {
+		index, err := tx.CreateBucketIfNotExists(domainsBucket)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(observationsBucket).ForEach(func(k, v []byte) error {
+			var o Observation
+			if err := json.Unmarshal(v, &o); err != nil {
+				return err
+			}
+			return index.Put(indexKey(o.Domain, o.Brand), []byte{})
+		})
+	},
+}
+
+// schemaVersion returns the schema version of the database.
+func schemaVersion(tx *bolt.Tx) int {
+	meta := tx.Bucket(metaBucket)
+	if meta == nil {
+		return 0
+	}
+
+	v := meta.Get(schemaKey)
+	if len(v) != 8 {
+		return 0
+	}
+
+	return int(binary.BigEndian.Uint64(v))
+}
+
+// migrate applies the missing migrations, each in its own transaction.
+func migrate(db *bolt.DB) error {
+	var version int
+
+	if err := db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+
+	for ; version < len(migrations); version++ {
+		err := db.Update(func(tx *bolt.Tx) error {
+			meta, err := tx.CreateBucketIfNotExists(metaBucket)
+			if err != nil {
+				return err
+			}
+
+			if err := migrations[version](tx); err != nil {
+				return err
+			}
+
+			v := make([]byte, 8)
+			binary.BigEndian.PutUint64(v, uint64(version+1))
+
+			return meta.Put(schemaKey, v)
+		})
+		if err != nil {
+			return fmt.Errorf("cannot migrate database to version %d: %w", version+1, err)
+		}
+	}
+
+	return nil
+}
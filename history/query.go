@@ -0,0 +1,124 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Query is the observation filter. Empty fields match everything.
+type Query struct {
+	// Brand is the brand name.
+	Brand string
+
+	// Since matches observations last seen at or after the time.
+	Since time.Time
+
+	// Until matches observations first seen before the time.
+	Until time.Time
+
+	// Action matches observations which had the action.
+	Action brandalert.Action
+
+	// Pattern matches domain names by the shell pattern, e.g. "paypa?-*.com".
+	// Matching ignores case.
+	Pattern string
+
+	// Limit is the maximum number of results. Zero means no limit.
+	Limit int
+}
+
+// match reports whether the observation matches the query.
+func (q *Query) match(o *Observation) (bool, error) {
+	if !q.Since.IsZero() && o.LastSeen.Before(q.Since) {
+		return false, nil
+	}
+	if !q.Until.IsZero() && !o.FirstSeen.Before(q.Until) {
+		return false, nil
+	}
+	if q.Action != "" && !o.HasAction(q.Action) {
+		return false, nil
+	}
+
+	if q.Pattern != "" {
+		ok, err := path.Match(strings.ToLower(q.Pattern), o.Domain)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %q: %w", q.Pattern, err)
+		}
+		return ok, nil
+	}
+
+	return true, nil
+}
+
+// Query returns the observations matching the query sorted by the first
+// seen time, oldest first.
+func (s *Store) Query(q Query) ([]Observation, error) {
+	if q.Pattern != "" {
+		if _, err := path.Match(q.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", q.Pattern, err)
+		}
+	}
+
+	var result []Observation
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(observationsBucket).Cursor()
+
+		var prefix []byte
+		if q.Brand != "" {
+			prefix = observationKey(q.Brand, "")
+		}
+
+		for k, v := c.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)); k, v = c.Next() {
+			var o Observation
+			if err := json.Unmarshal(v, &o); err != nil {
+				return fmt.Errorf("cannot decode observation %q: %w", k, err)
+			}
+
+			ok, err := q.match(&o)
+			if err != nil {
+				return err
+			}
+			if ok {
+				result = append(result, o)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].FirstSeen.Before(result[j].FirstSeen)
+	})
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+
+	return result, nil
+}
+
+// FirstSeen returns the earliest time any brand observed the domain.
+// It returns ErrNotFound if the domain was never observed.
+func (s *Store) FirstSeen(domain string) (time.Time, error) {
+	observations, err := s.Lookup(domain)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(observations) == 0 {
+		return time.Time{}, ErrNotFound
+	}
+
+	return observations[0].FirstSeen, nil
+}
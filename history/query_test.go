@@ -0,0 +1,73 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// TestQuery tests filtering observations.
+func TestQuery(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	day := func(d int) time.Time { return time.Date(2022, 11, d, 0, 0, 0, 0, time.UTC) }
+
+	for _, r := range []struct {
+		brand string
+		items []brandalert.DomainItem
+		seen  time.Time
+	}{
+		{brand: "PayPal", items: []brandalert.DomainItem{item(t, "paypa1-login.com", brandalert.Added, "2022-11-01")}, seen: day(1)},
+		{brand: "PayPal", items: []brandalert.DomainItem{item(t, "paypal-support.org", brandalert.Discovered, "2022-11-05")}, seen: day(5)},
+		{brand: "PayPal", items: []brandalert.DomainItem{item(t, "paypa1-login.com", brandalert.Dropped, "2022-11-10")}, seen: day(10)},
+		{brand: "Acme", items: []brandalert.DomainItem{item(t, "acme-login.com", brandalert.Added, "2022-11-07")}, seen: day(7)},
+	} {
+		if _, err := store.Record(r.brand, "", response(r.items...), r.seen); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		want    []string
+		wantErr string
+	}{
+		{name: "all", query: Query{}, want: []string{"paypa1-login.com", "paypal-support.org", "acme-login.com"}},
+		{name: "brand", query: Query{Brand: "PayPal"}, want: []string{"paypa1-login.com", "paypal-support.org"}},
+		{name: "since", query: Query{Since: day(6)}, want: []string{"paypa1-login.com", "acme-login.com"}},
+		{name: "until", query: Query{Until: day(5)}, want: []string{"paypa1-login.com"}},
+		{name: "action", query: Query{Action: brandalert.Dropped}, want: []string{"paypa1-login.com"}},
+		{name: "pattern", query: Query{Pattern: "*-LOGIN.com"}, want: []string{"paypa1-login.com", "acme-login.com"}},
+		{name: "combined", query: Query{Brand: "PayPal", Pattern: "paypa?-*", Action: brandalert.Discovered}, want: []string{"paypal-support.org"}},
+		{name: "limit", query: Query{Limit: 1}, want: []string{"paypa1-login.com"}},
+		{name: "no brand", query: Query{Brand: "Pay"}, want: nil},
+		{name: "invalid pattern", query: Query{Pattern: "[paypal"}, wantErr: `invalid pattern "[paypal": syntax error in pattern`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observations, err := store.Query(tt.query)
+			checkErr(t, err, tt.wantErr)
+
+			var got []string
+			for _, o := range observations {
+				got = append(got, o.Domain)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Query() got = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
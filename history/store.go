@@ -0,0 +1,310 @@
+// Package history persists every observed domain activity so that questions
+// like "when did we first see paypa1-login.com" can be answered months later.
+//
+// Observations are stored per brand and domain in an embedded bbolt database
+// with the first and last seen times, the query fingerprints and the history
+// of actions. The database schema is migrated on open.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when the observation does not exist.
+var ErrNotFound = errors.New("observation not found")
+
+// Event is the observed domain activity.
+type Event struct {
+	// Action is the domain action.
+	Action brandalert.Action `json:"action"`
+
+	// Date is the activity date reported by the API.
+	Date time.Time `json:"date"`
+
+	// Seen is the time the activity was first observed.
+	Seen time.Time `json:"seen"`
+}
+
+// Observation is the history of the domain found by the brand searches.
+type Observation struct {
+	// Brand is the brand name.
+	Brand string `json:"brand"`
+
+	// Domain is the domain name.
+	Domain string `json:"domain"`
+
+	// FirstSeen is the time the domain was first observed.
+	FirstSeen time.Time `json:"firstSeen"`
+
+	// LastSeen is the time the domain was last observed.
+	LastSeen time.Time `json:"lastSeen"`
+
+	// Fingerprints is the list of query fingerprints which found the domain.
+	Fingerprints []string `json:"fingerprints"`
+
+	// Events is the list of activities in the observation order.
+	Events []Event `json:"events"`
+}
+
+// LastAction returns the action of the last event.
+func (o *Observation) LastAction() brandalert.Action {
+	if len(o.Events) == 0 {
+		return ""
+	}
+
+	return o.Events[len(o.Events)-1].Action
+}
+
+// HasAction reports whether the domain had the action.
+func (o *Observation) HasAction(action brandalert.Action) bool {
+	for _, e := range o.Events {
+		if e.Action == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds the activity seen by the query to the observation. It reports
+// whether a new event was added.
+func (o *Observation) add(item brandalert.DomainItem, fingerprint string, seen time.Time) bool {
+	if o.FirstSeen.IsZero() || seen.Before(o.FirstSeen) {
+		o.FirstSeen = seen
+	}
+	if seen.After(o.LastSeen) {
+		o.LastSeen = seen
+	}
+
+	if fingerprint != "" {
+		i := sort.SearchStrings(o.Fingerprints, fingerprint)
+		if i == len(o.Fingerprints) || o.Fingerprints[i] != fingerprint {
+			o.Fingerprints = append(o.Fingerprints, "")
+			copy(o.Fingerprints[i+1:], o.Fingerprints[i:])
+			o.Fingerprints[i] = fingerprint
+		}
+	}
+
+	date := time.Time(item.Date)
+
+	for _, e := range o.Events {
+		if e.Action == item.Action && e.Date.Equal(date) {
+			return false
+		}
+	}
+
+	o.Events = append(o.Events, Event{Action: item.Action, Date: date, Seen: seen})
+
+	return true
+}
+
+// Fingerprint returns the fingerprint of the query. Terms are compared
+// ignoring case and order.
+func Fingerprint(include, exclude brandalert.SearchTerms, withTypos bool) string {
+	normalize := func(terms brandalert.SearchTerms) string {
+		v := make([]string, 0, len(terms))
+		for _, t := range terms {
+			v = append(v, strings.ToLower(strings.TrimSpace(t)))
+		}
+		sort.Strings(v)
+		return strings.Join(v, ",")
+	}
+
+	h := sha256.Sum256([]byte(fmt.Sprintf("include=%s;exclude=%s;typos=%t", normalize(include), normalize(exclude), withTypos)))
+
+	return hex.EncodeToString(h[:8])
+}
+
+// Store is the history database.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the database file and migrates its schema.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open database: %w", err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion returns the schema version of the database.
+func (s *Store) SchemaVersion() (int, error) {
+	var version int
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	})
+
+	return version, err
+}
+
+// Stats is the result of recording the response.
+type Stats struct {
+	// New is the number of domains observed for the first time.
+	New int
+
+	// Events is the number of new activities of known domains.
+	Events int
+}
+
+// Record records the domains of the response found by the brand query with
+// the fingerprint at the time.
+func (s *Store) Record(brand, fingerprint string, resp *brandalert.BrandAlertResponse, seen time.Time) (Stats, error) {
+	var stats Stats
+
+	if brand == "" {
+		return stats, errors.New("brand can not be empty")
+	}
+
+	if resp == nil || len(resp.DomainsList) == 0 {
+		return stats, nil
+	}
+
+	seen = seen.UTC()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		observations, index := tx.Bucket(observationsBucket), tx.Bucket(domainsBucket)
+
+		for _, item := range resp.DomainsList {
+			domain := normalizeDomain(item.DomainName)
+			if domain == "" {
+				continue
+			}
+
+			key := observationKey(brand, domain)
+
+			o := Observation{Brand: brand, Domain: domain}
+
+			if v := observations.Get(key); v != nil {
+				if err := json.Unmarshal(v, &o); err != nil {
+					return fmt.Errorf("cannot decode observation of %s: %w", domain, err)
+				}
+			} else {
+				stats.New++
+				if err := index.Put(indexKey(domain, brand), []byte{}); err != nil {
+					return err
+				}
+			}
+
+			existing := len(o.Events) > 0
+			if o.add(item, fingerprint, seen) && existing {
+				stats.Events++
+			}
+
+			v, err := json.Marshal(&o)
+			if err != nil {
+				return err
+			}
+
+			if err := observations.Put(key, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("cannot record observations: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Get returns the observation of the domain found by the brand searches.
+func (s *Store) Get(brand, domain string) (*Observation, error) {
+	var o *Observation
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(observationsBucket).Get(observationKey(brand, normalizeDomain(domain)))
+		if v == nil {
+			return ErrNotFound
+		}
+
+		o = &Observation{}
+		return json.Unmarshal(v, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Lookup returns the observations of the domain by all brands sorted by
+// the first seen time.
+func (s *Store) Lookup(domain string) ([]Observation, error) {
+	domain = normalizeDomain(domain)
+
+	var result []Observation
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		observations := tx.Bucket(observationsBucket)
+		prefix := indexKey(domain, "")
+
+		c := tx.Bucket(domainsBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)); k, _ = c.Next() {
+			brand := string(k[len(prefix):])
+
+			v := observations.Get(observationKey(brand, domain))
+			if v == nil {
+				continue
+			}
+
+			var o Observation
+			if err := json.Unmarshal(v, &o); err != nil {
+				return err
+			}
+			result = append(result, o)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].FirstSeen.Before(result[j].FirstSeen)
+	})
+
+	return result, nil
+}
+
+// normalizeDomain returns the domain name in lower case without the trailing dot.
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// observationKey returns the key of the observation.
+func observationKey(brand, domain string) []byte {
+	return []byte(brand + "\x00" + domain)
+}
+
+// indexKey returns the key of the domain index.
+func indexKey(domain, brand string) []byte {
+	return []byte(domain + "\x00" + brand)
+}
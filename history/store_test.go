@@ -0,0 +1,211 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// item returns the domain item with the date.
+func item(t *testing.T, domain string, action brandalert.Action, date string) brandalert.DomainItem {
+	d, err := brandalert.ParseTime(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return brandalert.DomainItem{DomainName: domain, Action: action, Date: d}
+}
+
+// response returns the response of the items.
+func response(items ...brandalert.DomainItem) *brandalert.BrandAlertResponse {
+	return &brandalert.BrandAlertResponse{DomainsList: items, DomainsCount: len(items)}
+}
+
+// TestRecord tests recording observations across reopens.
+func TestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2022, 11, d, 12, 0, 0, 0, time.UTC) }
+	paypal := Fingerprint(brandalert.SearchTerms{"PayPal"}, nil, true)
+
+	steps := []struct {
+		brand string
+		resp  *brandalert.BrandAlertResponse
+		seen  time.Time
+		want  Stats
+	}{
+		{
+			brand: "PayPal",
+			resp:  response(item(t, "PayPa1-Login.com", brandalert.Added, "2022-11-01"), item(t, "paypal-help.net", brandalert.Added, "2022-11-01")),
+			seen:  day(1),
+			want:  Stats{New: 2},
+		},
+		{
+			brand: "PayPal",
+			resp:  response(item(t, "paypa1-login.com", brandalert.Added, "2022-11-01")),
+			seen:  day(2),
+			want:  Stats{},
+		},
+		{
+			brand: "PayPal",
+			resp:  response(item(t, "paypa1-login.com", brandalert.Dropped, "2022-11-20")),
+			seen:  day(21),
+			want:  Stats{Events: 1},
+		},
+		{
+			brand: "Login",
+			resp:  response(item(t, "paypa1-login.com", brandalert.Dropped, "2022-11-20")),
+			seen:  day(22),
+			want:  Stats{New: 1},
+		},
+	}
+	for i, step := range steps {
+		got, err := store.Record(step.brand, paypal, step.resp, step.seen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != step.want {
+			t.Errorf("step %d: Record() got = %+v, want %+v", i, got, step.want)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if store, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	o, err := store.Get("PayPal", "paypa1-login.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !o.FirstSeen.Equal(day(1)) || !o.LastSeen.Equal(day(21)) || o.LastAction() != brandalert.Dropped ||
+		len(o.Events) != 2 || !reflect.DeepEqual(o.Fingerprints, []string{paypal}) {
+		t.Errorf("Get() got = %+v", o)
+	}
+
+	if _, err := store.Get("Login", "paypal-help.net"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, ErrNotFound)
+	}
+
+	observations, err := store.Lookup("PAYPA1-LOGIN.COM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 2 || observations[0].Brand != "PayPal" || observations[1].Brand != "Login" {
+		t.Errorf("Lookup() got = %+v", observations)
+	}
+
+	first, err := store.FirstSeen("paypa1-login.com")
+	if err != nil || !first.Equal(day(1)) {
+		t.Errorf("FirstSeen() got = %v, %v", first, err)
+	}
+
+	if _, err := store.FirstSeen("example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FirstSeen() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+// TestMigrate tests upgrading the version 1 database.
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucket(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, 1)
+		if err := meta.Put(schemaKey, v); err != nil {
+			return err
+		}
+
+		observations, err := tx.CreateBucket(observationsBucket)
+		if err != nil {
+			return err
+		}
+
+		o, _ := json.Marshal(Observation{Brand: "PayPal", Domain: "paypa1-login.com", FirstSeen: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)})
+		return observations.Put(observationKey("PayPal", "paypa1-login.com"), o)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version, err := store.SchemaVersion(); err != nil || version != len(migrations) {
+		t.Errorf("SchemaVersion() got = %v, %v", version, err)
+	}
+
+	if observations, err := store.Lookup("paypa1-login.com"); err != nil || len(observations) != 1 {
+		t.Errorf("Lookup() got = %+v, %v", observations, err)
+	}
+
+	store.Close()
+
+	// Databases of newer versions are rejected.
+	db, err = bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Update(func(tx *bolt.Tx) error {
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, uint64(len(migrations)+1))
+		return tx.Bucket(metaBucket).Put(schemaKey, v)
+	})
+	db.Close()
+
+	_, err = Open(path)
+	checkErr(t, err, "database schema version 3 is newer than supported version 2")
+}
+
+// TestFingerprint tests the Fingerprint function.
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint(brandalert.SearchTerms{"whois", "XML"}, brandalert.SearchTerms{"api"}, false)
+
+	if b := Fingerprint(brandalert.SearchTerms{"xml", "whois"}, brandalert.SearchTerms{"API"}, false); a != b {
+		t.Errorf("Fingerprint() got = %v, want %v", b, a)
+	}
+
+	if b := Fingerprint(brandalert.SearchTerms{"whois", "xml"}, brandalert.SearchTerms{"api"}, true); a == b {
+		t.Errorf("Fingerprint() got same fingerprint with typos")
+	}
+
+	if len(a) != 16 {
+		t.Errorf("Fingerprint() got = %v", a)
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
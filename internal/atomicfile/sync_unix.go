@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package atomicfile
+
+import "os"
+
+// syncDir syncs the directory, so the renamed entry is persisted.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+
+	return d.Close()
+}
@@ -0,0 +1,6 @@
+package atomicfile
+
+// syncDir does nothing because directories can not be synced on Windows.
+func syncDir(string) error {
+	return nil
+}
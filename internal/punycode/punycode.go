@@ -0,0 +1,258 @@
+// Package punycode implements the Punycode encoding (RFC 3492) used by
+// internationalized domain names.
+package punycode
+
+import (
+	"errors"
+	"strings"
+)
+
+// acePrefix is the prefix of the Punycode encoded domain labels.
+const acePrefix = "xn--"
+
+const (
+	base        = 36
+	tMin        = 1
+	tMax        = 26
+	skew        = 38
+	damp        = 700
+	initialBias = 72
+	initialN    = 128
+	maxInt      = int(^uint32(0) >> 1)
+)
+
+// ErrInvalid is returned when the input is not a valid Punycode string.
+var ErrInvalid = errors.New("punycode: invalid input")
+
+// adapt is the bias adaptation function from RFC 3492 section 6.1.
+func adapt(delta, numPoints int, firstTime bool) int {
+	if firstTime {
+		delta /= damp
+	} else {
+		delta /= 2
+	}
+
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((base-tMin)*tMax)/2 {
+		delta /= base - tMin
+		k += base
+	}
+
+	return k + (base-tMin+1)*delta/(delta+skew)
+}
+
+// threshold returns the threshold for the digit position k.
+func threshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return tMin
+	case k >= bias+tMax:
+		return tMax
+	default:
+		return k - bias
+	}
+}
+
+// decodeDigit returns the numeric value of a basic code point.
+func decodeDigit(c byte) (int, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c-'0') + 26, true
+	case 'A' <= c && c <= 'Z':
+		return int(c - 'A'), true
+	case 'a' <= c && c <= 'z':
+		return int(c - 'a'), true
+	}
+
+	return 0, false
+}
+
+// encodeDigit returns the basic code point representing the digit.
+func encodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+// Encode encodes the Unicode string to Punycode without the "xn--" prefix.
+func Encode(s string) (string, error) {
+	runes := []rune(s)
+
+	output := make([]byte, 0, len(s)+1)
+	for _, r := range runes {
+		if r < 0x80 {
+			output = append(output, byte(r))
+		}
+	}
+
+	b := len(output)
+	h := b
+	if b > 0 {
+		output = append(output, '-')
+	}
+
+	n, delta, bias := initialN, 0, initialBias
+
+	for h < len(runes) {
+		m := maxInt
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+
+		if m-n > (maxInt-delta)/(h+1) {
+			return "", ErrInvalid
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+				if delta == maxInt {
+					return "", ErrInvalid
+				}
+			}
+
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := base; ; k += base {
+				t := threshold(k, bias)
+				if q < t {
+					break
+				}
+				output = append(output, encodeDigit(t+(q-t)%(base-t)))
+				q = (q - t) / (base - t)
+			}
+			output = append(output, encodeDigit(q))
+
+			bias = adapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+
+		delta++
+		n++
+	}
+
+	return string(output), nil
+}
+
+// Decode decodes the Punycode string without the "xn--" prefix.
+func Decode(s string) (string, error) {
+	var output []rune
+
+	if pos := strings.LastIndexByte(s, '-'); pos >= 0 {
+		for i := 0; i < pos; i++ {
+			if s[i] >= 0x80 {
+				return "", ErrInvalid
+			}
+			output = append(output, rune(s[i]))
+		}
+		s = s[pos+1:]
+	}
+
+	n, bias, i := initialN, initialBias, 0
+
+	for p := 0; p < len(s); {
+		oldi, w := i, 1
+
+		for k := base; ; k += base {
+			if p == len(s) {
+				return "", ErrInvalid
+			}
+
+			digit, ok := decodeDigit(s[p])
+			if !ok {
+				return "", ErrInvalid
+			}
+			p++
+
+			if digit > (maxInt-i)/w {
+				return "", ErrInvalid
+			}
+			i += digit * w
+
+			t := threshold(k, bias)
+			if digit < t {
+				break
+			}
+
+			if w > maxInt/(base-t) {
+				return "", ErrInvalid
+			}
+			w *= base - t
+		}
+
+		length := len(output) + 1
+		bias = adapt(i-oldi, length, oldi == 0)
+
+		if i/length > maxInt-n {
+			return "", ErrInvalid
+		}
+		n += i / length
+		i %= length
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return string(output), nil
+}
+
+// ToUnicode converts every Punycode label of the domain name to Unicode.
+func ToUnicode(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), acePrefix) {
+			continue
+		}
+
+		decoded, err := Decode(label[len(acePrefix):])
+		if err != nil {
+			return "", err
+		}
+		labels[i] = decoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// ToASCII converts every non-ASCII label of the domain name to Punycode.
+func ToASCII(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+
+		encoded, err := Encode(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = acePrefix + encoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// isASCII reports whether the string contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
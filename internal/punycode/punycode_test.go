@@ -0,0 +1,97 @@
+package punycode
+
+import (
+	"testing"
+)
+
+// TestDecode tests the Decode function.
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "mnchen-3ya",
+			want: "münchen",
+		},
+		{
+			name: "80ak6aa92e",
+			want: "аррӏе",
+		},
+		{
+			name: "bcher-kva",
+			want: "bücher",
+		},
+		{
+			name: "abc-",
+			want: "abc",
+		},
+		{
+			name:    "mnchen-3y!",
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "mnchen-3",
+			wantErr: ErrInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.name)
+			if err != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Decode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEncode tests the Encode function.
+func TestEncode(t *testing.T) {
+	for _, want := range []string{"mnchen-3ya", "80ak6aa92e", "bcher-kva", "abc-", "fiqs8s"} {
+		t.Run(want, func(t *testing.T) {
+			decoded, err := Decode(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Encode(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != want {
+				t.Errorf("Encode() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestToUnicode tests the ToUnicode function.
+func TestToUnicode(t *testing.T) {
+	got, err := ToUnicode("www.xn--80ak6aa92e.com")
+	if err != nil {
+		t.Fatalf("ToUnicode() error = %v", err)
+	}
+
+	if got != "www.аррӏе.com" {
+		t.Errorf("ToUnicode() got = %v, want %v", got, "www.аррӏе.com")
+	}
+}
+
+// TestToASCII tests the ToASCII function.
+func TestToASCII(t *testing.T) {
+	got, err := ToASCII("www.аррӏе.中国")
+	if err != nil {
+		t.Fatalf("ToASCII() error = %v", err)
+	}
+
+	if got != "www.xn--80ak6aa92e.xn--fiqs8s" {
+		t.Errorf("ToASCII() got = %v, want %v", got, "www.xn--80ak6aa92e.xn--fiqs8s")
+	}
+}
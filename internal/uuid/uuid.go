@@ -0,0 +1,81 @@
+// Package uuid implements name-based (version 5) UUIDs used as
+// deterministic identifiers of exported objects.
+package uuid
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// UUID is the RFC 4122 UUID.
+type UUID [16]byte
+
+// ErrInvalid is returned when the string is not a valid UUID.
+var ErrInvalid = errors.New("uuid: invalid format")
+
+// Parse parses the UUID in the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form.
+func Parse(s string) (UUID, error) {
+	var u UUID
+
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, ErrInvalid
+	}
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != len(u) {
+		return u, ErrInvalid
+	}
+
+	copy(u[:], b)
+
+	return u, nil
+}
+
+// MustParse is like Parse but panics if the string can not be parsed.
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// NewV5 returns the version 5 UUID of the name in the namespace.
+func NewV5(namespace UUID, name string) UUID {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+
+	var u UUID
+	copy(u[:], h.Sum(nil))
+
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return u
+}
+
+// Version returns the UUID version.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// String returns the UUID in the canonical form.
+func (u UUID) String() string {
+	var buf [36]byte
+
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
@@ -0,0 +1,39 @@
+package uuid
+
+import (
+	"testing"
+)
+
+// TestNewV5 tests the NewV5 function.
+func TestNewV5(t *testing.T) {
+	dns := MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	got := NewV5(dns, "www.example.com")
+	if got.String() != "2ed6657d-e927-568b-95e1-2665a8aea6a2" || got.Version() != 5 {
+		t.Errorf("NewV5() got = %v, want %v", got, "2ed6657d-e927-568b-95e1-2665a8aea6a2")
+	}
+}
+
+// TestParse tests the Parse function.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", nil},
+		{"6ba7b8109dad11d180b400c04fd430c8", ErrInvalid},
+		{"6ba7b810-9dad-11d1-80b4-00c04fd430cg", ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Parse(tt.name)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && u.String() != tt.name {
+				t.Errorf("Parse() got = %v, want %v", u, tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+// Package misp exports Brand Alert API domains as MISP events so they can be
+// imported into and shared through MISP instances. Daily runs can append to
+// the same event file to accumulate the domains into one event.
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/internal/atomicfile"
+	"github.com/whois-api-llc/brand-alert-go/internal/uuid"
+)
+
+// ThreatLevel is the MISP event threat level.
+type ThreatLevel int
+
+// List of MISP threat levels.
+const (
+	ThreatLevelHigh      ThreatLevel = 1
+	ThreatLevelMedium    ThreatLevel = 2
+	ThreatLevelLow       ThreatLevel = 3
+	ThreatLevelUndefined ThreatLevel = 4
+)
+
+// Analysis is the MISP event analysis stage.
+type Analysis int
+
+// List of MISP analysis stages.
+const (
+	AnalysisInitial   Analysis = 0
+	AnalysisOngoing   Analysis = 1
+	AnalysisCompleted Analysis = 2
+)
+
+// Distribution is the MISP distribution level.
+type Distribution int
+
+// List of MISP distribution levels.
+const (
+	DistributionOrganisation Distribution = 0
+	DistributionCommunity    Distribution = 1
+	DistributionConnected    Distribution = 2
+	DistributionAll          Distribution = 3
+)
+
+const (
+	// dateFormat is the MISP event date format.
+	dateFormat = "2006-01-02"
+
+	// seenFormat is the MISP first_seen format.
+	seenFormat = "2006-01-02T15:04:05.000000-07:00"
+
+	// attributeType is the MISP type of the exported attributes.
+	attributeType = "domain"
+
+	// attributeCategory is the MISP category of the exported attributes.
+	attributeCategory = "Network activity"
+)
+
+// namespace is the namespace of the identifiers created by this package.
+var namespace = uuid.MustParse("8f0e6f3c-3f2a-5b1e-9d43-6b2b1e4d7a10")
+
+// Tag is the MISP tag.
+type Tag struct {
+	Name string `json:"name"`
+
+	// Extra keeps the fields not modeled by this package, e.g. colour.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Attribute is the MISP attribute.
+type Attribute struct {
+	UUID         string       `json:"uuid"`
+	Type         string       `json:"type"`
+	Category     string       `json:"category"`
+	Value        string       `json:"value"`
+	ToIDS        bool         `json:"to_ids"`
+	Distribution Distribution `json:"distribution,string"`
+	Comment      string       `json:"comment,omitempty"`
+	Timestamp    int64        `json:"timestamp,string"`
+	FirstSeen    string       `json:"first_seen,omitempty"`
+	Tag          []Tag        `json:"Tag,omitempty"`
+
+	// Extra keeps the fields not modeled by this package, e.g. object_relation.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Event is the MISP event.
+type Event struct {
+	UUID          string       `json:"uuid"`
+	Info          string       `json:"info"`
+	Date          string       `json:"date"`
+	ThreatLevelID ThreatLevel  `json:"threat_level_id,string"`
+	Analysis      Analysis     `json:"analysis,string"`
+	Distribution  Distribution `json:"distribution,string"`
+	Published     bool         `json:"published"`
+	Timestamp     int64        `json:"timestamp,string"`
+	Attribute     []Attribute  `json:"Attribute"`
+	Tag           []Tag        `json:"Tag,omitempty"`
+
+	// Extra keeps the fields not modeled by this package, e.g. Object or
+	// Galaxy, so that they survive AppendFile.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the tag keeping the unknown fields in Extra.
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type plain Tag
+	return unmarshalExtra(data, (*plain)(t), &t.Extra)
+}
+
+// MarshalJSON encodes the tag with the fields of Extra.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type plain Tag
+	return marshalExtra(plain(t), t.Extra)
+}
+
+// UnmarshalJSON decodes the attribute keeping the unknown fields in Extra.
+func (a *Attribute) UnmarshalJSON(data []byte) error {
+	type plain Attribute
+	return unmarshalExtra(data, (*plain)(a), &a.Extra)
+}
+
+// MarshalJSON encodes the attribute with the fields of Extra.
+func (a Attribute) MarshalJSON() ([]byte, error) {
+	type plain Attribute
+	return marshalExtra(plain(a), a.Extra)
+}
+
+// UnmarshalJSON decodes the event keeping the unknown fields in Extra.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type plain Event
+	return unmarshalExtra(data, (*plain)(e), &e.Extra)
+}
+
+// MarshalJSON encodes the event with the fields of Extra.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type plain Event
+	return marshalExtra(plain(e), e.Extra)
+}
+
+// unmarshalExtra decodes the JSON object into the struct pointed to by v and
+// its fields missing in the struct into extra.
+func unmarshalExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		delete(fields, name)
+	}
+
+	*extra = nil
+	if len(fields) > 0 {
+		*extra = fields
+	}
+
+	return nil
+}
+
+// marshalExtra encodes the struct appending the fields of extra in the
+// order of their names.
+func marshalExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	data := bytes.TrimSpace(b.Bytes())
+	if len(extra) == 0 {
+		return data, nil
+	}
+
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data = data[:len(data)-1]
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) > 1 {
+			data = append(data, ',')
+		}
+		data = append(append(append(data, key...), ':'), extra[name]...)
+	}
+
+	return append(data, '}'), nil
+}
+
+// document is the MISP event JSON document.
+type document struct {
+	Event *Event `json:"Event"`
+}
+
+// Params is used to create events. None of parameters are mandatory.
+type Params struct {
+	// Brand is the brand name added as a tag to the event and the attributes.
+	Brand string
+
+	// Info is the event description. Default: "Brand Alert: <Brand> lookalike domains".
+	Info string
+
+	// ThreatLevel is the event threat level. Default: ThreatLevelLow.
+	ThreatLevel ThreatLevel
+
+	// Analysis is the event analysis stage. Default: AnalysisInitial.
+	Analysis Analysis
+
+	// Distribution is the event and attributes distribution level.
+	// Default: DistributionOrganisation.
+	Distribution Distribution
+
+	// Tags is the list of additional event tags, e.g. "tlp:amber".
+	Tags []string
+
+	// NoIDS disables the IDS flag of the attributes.
+	NoIDS bool
+
+	// Now is the event and attributes timestamp. Default: the current time.
+	Now time.Time
+}
+
+// withDefaults returns the parameters with the default values set.
+func (p Params) withDefaults() Params {
+	if p.Info == "" {
+		p.Info = "Brand Alert: lookalike domains"
+		if p.Brand != "" {
+			p.Info = "Brand Alert: " + p.Brand + " lookalike domains"
+		}
+	}
+	if p.ThreatLevel == 0 {
+		p.ThreatLevel = ThreatLevelLow
+	}
+	if p.Now.IsZero() {
+		p.Now = time.Now()
+	}
+
+	return p
+}
+
+// ActionTag returns the name of the tag of the domain action.
+func ActionTag(action brandalert.Action) string {
+	return `brand-alert:action="` + string(action) + `"`
+}
+
+// BrandTag returns the name of the tag of the brand.
+func BrandTag(brand string) string {
+	return `brand-alert:brand="` + brand + `"`
+}
+
+// NewEvent creates the event with the domains of the response.
+func NewEvent(resp *brandalert.BrandAlertResponse, params Params) *Event {
+	params = params.withDefaults()
+
+	event := &Event{
+		UUID:          uuid.NewV5(namespace, params.Info+"|"+params.Now.UTC().Format(dateFormat)).String(),
+		Info:          params.Info,
+		Date:          params.Now.UTC().Format(dateFormat),
+		ThreatLevelID: params.ThreatLevel,
+		Analysis:      params.Analysis,
+		Distribution:  params.Distribution,
+		Timestamp:     params.Now.Unix(),
+		Attribute:     []Attribute{},
+	}
+
+	tags := params.Tags
+	if params.Brand != "" {
+		tags = append([]string{BrandTag(params.Brand)}, tags...)
+	}
+	for _, name := range tags {
+		event.Tag = addTag(event.Tag, name)
+	}
+
+	if resp != nil {
+		event.Add(resp.DomainsList, params)
+	}
+
+	return event
+}
+
+// Add adds the domains to the event as domain attributes. Known domains are
+// not duplicated: their first_seen date is moved back if the item is older
+// and the action tag is added. It returns the number of new attributes.
+// Attributes are kept sorted by value.
+func (e *Event) Add(items []brandalert.DomainItem, params Params) int {
+	params = params.withDefaults()
+
+	index := make(map[string]int, len(e.Attribute))
+	for i, attr := range e.Attribute {
+		if attr.Type == attributeType {
+			index[strings.ToLower(attr.Value)] = i
+		}
+	}
+
+	var added int
+
+	for _, item := range items {
+		value := strings.TrimSuffix(strings.ToLower(item.DomainName), ".")
+		if value == "" {
+			continue
+		}
+
+		i, ok := index[value]
+		if !ok {
+			i = len(e.Attribute)
+			index[value] = i
+			added++
+
+			e.Attribute = append(e.Attribute, Attribute{
+				UUID:         uuid.NewV5(namespace, e.UUID+"|"+attributeType+"|"+value).String(),
+				Type:         attributeType,
+				Category:     attributeCategory,
+				Value:        value,
+				ToIDS:        !params.NoIDS,
+				Distribution: params.Distribution,
+				Comment:      "Brand Alert API",
+			})
+		}
+
+		attr := &e.Attribute[i]
+		changed := !ok
+
+		if !item.Date.IsZero() && olderThan(time.Time(item.Date), attr.FirstSeen) {
+			attr.FirstSeen = time.Time(item.Date).UTC().Format(seenFormat)
+			changed = true
+		}
+
+		var names []string
+		if params.Brand != "" {
+			names = append(names, BrandTag(params.Brand))
+		}
+		if item.Action != "" {
+			names = append(names, ActionTag(item.Action))
+		}
+		for _, name := range names {
+			if !hasTag(attr.Tag, name) {
+				attr.Tag = addTag(attr.Tag, name)
+				changed = true
+			}
+		}
+
+		if changed {
+			attr.Timestamp = params.Now.Unix()
+			e.Timestamp = params.Now.Unix()
+		}
+	}
+
+	sort.SliceStable(e.Attribute, func(i, j int) bool {
+		return e.Attribute[i].Value < e.Attribute[j].Value
+	})
+
+	return added
+}
+
+// Write writes the event of the response domains as JSON.
+func Write(w io.Writer, resp *brandalert.BrandAlertResponse, params Params) error {
+	return WriteEvent(w, NewEvent(resp, params))
+}
+
+// WriteEvent writes the event as a MISP event JSON document.
+func WriteEvent(w io.Writer, event *Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(document{Event: event}); err != nil {
+		return fmt.Errorf("cannot write event: %w", err)
+	}
+
+	return nil
+}
+
+// Read reads the MISP event JSON document.
+func Read(r io.Reader) (*Event, error) {
+	var doc document
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cannot read event: %w", err)
+	}
+
+	if doc.Event == nil {
+		return nil, fmt.Errorf("cannot read event: no Event object")
+	}
+
+	return doc.Event, nil
+}
+
+// AppendFile adds the response domains to the event stored in the file.
+// The event is created if the file doesn't exist. The file is replaced
+// atomically. It returns the number of new attributes.
+func AppendFile(path string, resp *brandalert.BrandAlertResponse, params Params) (int, error) {
+	var event *Event
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		event, err = Read(bytes.NewReader(data))
+		if err != nil {
+			return 0, err
+		}
+	case os.IsNotExist(err):
+		event = NewEvent(nil, params)
+	default:
+		return 0, fmt.Errorf("cannot read event: %w", err)
+	}
+
+	var added int
+	if resp != nil {
+		added = event.Add(resp.DomainsList, params)
+	}
+
+	var b bytes.Buffer
+	if err := WriteEvent(&b, event); err != nil {
+		return 0, err
+	}
+
+	if err := atomicfile.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return 0, fmt.Errorf("cannot write event: %w", err)
+	}
+
+	return added, nil
+}
+
+// olderThan reports whether the time is before the first_seen value.
+// Any time is older than an empty or unparsable value.
+func olderThan(t time.Time, firstSeen string) bool {
+	seen, err := time.Parse(time.RFC3339Nano, firstSeen)
+	if err != nil {
+		return true
+	}
+
+	return t.Before(seen)
+}
+
+// hasTag reports whether the tag with the name is in the list.
+func hasTag(tags []Tag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addTag adds the tag with the name to the list if it's not there yet.
+func addTag(tags []Tag, name string) []Tag {
+	if hasTag(tags, name) {
+		return tags
+	}
+
+	return append(tags, Tag{Name: name})
+}
@@ -0,0 +1,223 @@
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+func date(day int) brandalert.Time {
+	return brandalert.Time(time.Date(2022, 10, day, 0, 0, 0, 0, time.UTC))
+}
+
+// TestWrite tests the Write function.
+func TestWrite(t *testing.T) {
+	resp := &brandalert.BrandAlertResponse{
+		DomainsList: []brandalert.DomainItem{
+			{DomainName: "WhoisDodster.com", Action: brandalert.Added, Date: date(30)},
+			{DomainName: "batchwhois.com", Action: brandalert.Dropped, Date: date(29)},
+			{DomainName: "whoisdodster.com", Action: brandalert.Updated, Date: date(28)},
+		},
+		DomainsCount: 3,
+	}
+
+	params := Params{
+		Brand: "WhoisXML",
+		Tags:  []string{"tlp:amber"},
+		Now:   time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var b bytes.Buffer
+	if err := Write(&b, resp, params); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	event := doc["Event"]
+
+	want := map[string]interface{}{
+		"info":            "Brand Alert: WhoisXML lookalike domains",
+		"date":            "2022-11-01",
+		"threat_level_id": "3",
+		"analysis":        "0",
+		"distribution":    "0",
+		"published":       false,
+		"timestamp":       "1667304000",
+	}
+	for k, v := range want {
+		if event[k] != v {
+			t.Errorf("Write() got event %s = %v, want %v", k, event[k], v)
+		}
+	}
+
+	got, err := Read(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tags := tagNames(got.Tag); !reflect.DeepEqual(tags, []string{`brand-alert:brand="WhoisXML"`, "tlp:amber"}) {
+		t.Errorf("Write() got event tags = %v", tags)
+	}
+
+	if len(got.Attribute) != 2 {
+		t.Fatalf("Write() got %d attributes, want 2", len(got.Attribute))
+	}
+
+	attr := got.Attribute[1]
+	if attr.Value != "whoisdodster.com" || attr.Type != "domain" || attr.Category != "Network activity" ||
+		!attr.ToIDS || attr.FirstSeen != "2022-10-28T00:00:00.000000+00:00" {
+		t.Errorf("Write() got attribute = %+v", attr)
+	}
+
+	wantTags := []string{`brand-alert:brand="WhoisXML"`, `brand-alert:action="added"`, `brand-alert:action="updated"`}
+	if tags := tagNames(attr.Tag); !reflect.DeepEqual(tags, wantTags) {
+		t.Errorf("Write() got attribute tags = %v, want %v", tags, wantTags)
+	}
+}
+
+// TestAppendFile tests the AppendFile function.
+func TestAppendFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "event.json")
+
+	first := &brandalert.BrandAlertResponse{
+		DomainsList: []brandalert.DomainItem{
+			{DomainName: "whoisdodster.com", Action: brandalert.Added, Date: date(30)},
+		},
+	}
+
+	second := &brandalert.BrandAlertResponse{
+		DomainsList: []brandalert.DomainItem{
+			{DomainName: "whoisdodster.com", Action: brandalert.Dropped, Date: date(31)},
+			{DomainName: "batchwhois.com", Action: brandalert.Added, Date: date(31)},
+		},
+	}
+
+	params := Params{Brand: "WhoisXML", Now: time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)}
+
+	added, err := AppendFile(path, first, params)
+	if err != nil || added != 1 {
+		t.Fatalf("AppendFile() got = %d, %v", added, err)
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params.Now = params.Now.AddDate(0, 0, 1)
+
+	added, err = AppendFile(path, second, params)
+	if err != nil || added != 1 {
+		t.Fatalf("AppendFile() got = %d, %v", added, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	event, err := Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prev, err := Read(bytes.NewReader(before))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.UUID != prev.UUID || event.Date != "2022-10-31" || len(event.Attribute) != 2 {
+		t.Fatalf("AppendFile() got event = %+v", event)
+	}
+
+	attr := event.Attribute[1]
+	if attr.UUID != prev.Attribute[0].UUID || attr.FirstSeen != "2022-10-30T00:00:00.000000+00:00" ||
+		!reflect.DeepEqual(tagNames(attr.Tag), []string{`brand-alert:brand="WhoisXML"`, `brand-alert:action="added"`, `brand-alert:action="dropped"`}) {
+		t.Errorf("AppendFile() got attribute = %+v", attr)
+	}
+
+	if event.Timestamp != params.Now.Unix() {
+		t.Errorf("AppendFile() got timestamp = %d, want %d", event.Timestamp, params.Now.Unix())
+	}
+
+	// Fields not modeled by the package are kept.
+	var raw map[string]map[string]interface{}
+	if err := json.Unmarshal(before, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := raw["Event"]
+	ev["Object"] = []map[string]string{{"name": "domain-ip"}}
+	ev["sharing_group_id"] = "7"
+	ev["Attribute"].([]interface{})[0].(map[string]interface{})["sharing_group_id"] = "7"
+	ev["Tag"].([]interface{})[0].(map[string]interface{})["colour"] = "#ff0000"
+
+	edited, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, edited, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AppendFile(path, second, params); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Event struct {
+			Object         []map[string]string
+			SharingGroupID string `json:"sharing_group_id"`
+			Attribute      []struct {
+				SharingGroupID string `json:"sharing_group_id"`
+			}
+			Tag []map[string]string
+		}
+	}
+	if err := json.Unmarshal(after, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Event.Object) != 1 || doc.Event.Object[0]["name"] != "domain-ip" || doc.Event.SharingGroupID != "7" ||
+		len(doc.Event.Attribute) != 2 || doc.Event.Attribute[1].SharingGroupID != "7" || doc.Event.Tag[0]["colour"] != "#ff0000" {
+		t.Errorf("AppendFile() dropped the unknown fields:\n%s", after)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"response":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AppendFile(path, second, params)
+	checkErr(t, err, "cannot read event: no Event object")
+}
+
+func tagNames(tags []Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
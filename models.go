@@ -1,8 +1,10 @@
 package brandalert
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -22,35 +24,155 @@ var emptyTime Time
 
 const dateFormat = "2006-01-02"
 
-// UnmarshalJSON decodes time as Brand Alert API does.
+// timeFormats is the list of accepted time formats. Brand Alert API returns
+// dates, but occasionally it returns full timestamps.
+var timeFormats = []string{
+	dateFormat,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// ParseTime parses the date or timestamp in UTC. An empty string results in zero Time.
+func ParseTime(s string) (Time, error) {
+	return ParseTimeInLocation(s, time.UTC)
+}
+
+// ParseTimeInLocation parses the date or timestamp without a time zone in the
+// given location. Nil location means UTC. An empty string results in zero Time.
+// Use it or Time.In to interpret the dates of the response in a local time zone.
+func ParseTimeInLocation(s string, loc *time.Location) (Time, error) {
+	if s == "" {
+		return emptyTime, nil
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var firstErr error
+
+	for _, layout := range timeFormats {
+		v, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return Time(v), nil
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return emptyTime, firstErr
+}
+
+// UnmarshalJSON decodes time as Brand Alert API does. Dates and timestamps
+// without a time zone are decoded in UTC.
 func (t *Time) UnmarshalJSON(b []byte) error {
 	str, err := unmarshalString(b)
 	if err != nil {
 		return err
 	}
-	if str == "" {
-		*t = emptyTime
-		return nil
-	}
-	v, err := time.Parse(dateFormat, str)
+	v, err := ParseTime(str)
 	if err != nil {
 		return err
 	}
-	*t = Time(v)
+	*t = v
 	return nil
 }
 
 // MarshalJSON encodes time as Brand Alert API does.
 func (t Time) MarshalJSON() ([]byte, error) {
-	if t == emptyTime {
-		return []byte(`""`), nil
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalText decodes the date or timestamp in UTC. An empty text results in zero Time.
+func (t *Time) UnmarshalText(b []byte) error {
+	v, err := ParseTime(string(b))
+	if err != nil {
+		return err
 	}
-	return []byte(`"` + time.Time(t).Format(dateFormat) + `"`), nil
+	*t = v
+	return nil
+}
+
+// MarshalText encodes time as a date. Zero Time is encoded as an empty text.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Scan implements the sql.Scanner interface. NULL results in zero Time.
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = emptyTime
+		return nil
+	case time.Time:
+		*t = Time(v)
+		return nil
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	}
+
+	return fmt.Errorf("cannot scan %T into Time", src)
+}
+
+// Value implements the driver.Valuer interface. Zero Time results in NULL.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return time.Time(t), nil
+}
+
+// String returns time as a date. Zero Time results in an empty string.
+func (t Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return time.Time(t).Format(dateFormat)
+}
+
+// IsZero reports whether t is zero time.
+func (t Time) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+// Before reports whether t is before u.
+func (t Time) Before(u Time) bool {
+	return time.Time(t).Before(time.Time(u))
+}
+
+// After reports whether t is after u.
+func (t Time) After(u Time) bool {
+	return time.Time(t).After(time.Time(u))
+}
+
+// Equal reports whether t and u represent the same time instant.
+func (t Time) Equal(u Time) bool {
+	return time.Time(t).Equal(time.Time(u))
+}
+
+// In returns t with the location set to loc.
+func (t Time) In(loc *time.Location) Time {
+	return Time(time.Time(t).In(loc))
 }
 
 // SearchTerms is a set of including or excluding search terms.
 type SearchTerms []string
 
+// OrNil returns the pointer to the terms or nil if there are none, so that
+// empty terms are left out of the request.
+func (s SearchTerms) OrNil() *SearchTerms {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return &s
+}
+
 // brandAlertRequest is the request struct for Brand Alert API.
 type brandAlertRequest struct {
 	// APIKey is the user's API key.
@@ -79,29 +201,124 @@ type brandAlertRequest struct {
 }
 
 // Action is a wrapper on string.
+//
+// Actions are decoded leniently: UnmarshalJSON and UnmarshalText never fail on
+// unknown actions and decode them to Unknown, so that new actions in API
+// responses and stored data do not break decoding. User input should be
+// validated strictly with ParseAction, which Set does for command-line flags.
 type Action string
 
 // List of possible actions.
 const (
 	Added      Action = "added"
-	Updated           = "updated"
-	Dropped           = "dropped"
-	Discovered        = "discovered"
+	Updated    Action = "updated"
+	Dropped    Action = "dropped"
+	Discovered Action = "discovered"
+
+	// Unknown is the action not known to this library. It is never returned by
+	// ParseAction, but unknown actions in API responses are decoded to it.
+	Unknown Action = "unknown"
 )
 
-var _ = []Action{
+// actions is the list of valid actions.
+var actions = []Action{
 	Added,
 	Updated,
 	Dropped,
 	Discovered,
 }
 
+// UnknownActionError is returned when the action is not known.
+type UnknownActionError struct {
+	// DomainName is the domain name with the unknown action, if any.
+	DomainName string
+
+	// Value is the unknown action.
+	Value string
+}
+
+// Error returns error message as a string.
+func (e *UnknownActionError) Error() string {
+	if e.DomainName != "" {
+		return `unknown action "` + e.Value + `" of domain "` + e.DomainName + `"`
+	}
+
+	return `unknown action "` + e.Value + `"`
+}
+
+// ParseAction parses the action ignoring case. Unknown actions are returned as
+// Unknown along with UnknownActionError.
+func ParseAction(s string) (Action, error) {
+	v := Action(strings.ToLower(strings.TrimSpace(s)))
+	if v.Valid() {
+		return v, nil
+	}
+
+	return Unknown, &UnknownActionError{Value: s}
+}
+
+// Valid reports whether the action is one of the known actions.
+func (a Action) Valid() bool {
+	for _, v := range actions {
+		if a == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the action as a string.
+func (a Action) String() string {
+	return string(a)
+}
+
+// MarshalText encodes the action as a string.
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a), nil
+}
+
+// UnmarshalText decodes the action ignoring case. Unknown actions are decoded
+// to Unknown.
+func (a *Action) UnmarshalText(b []byte) error {
+	*a, _ = ParseAction(string(b))
+
+	return nil
+}
+
+// UnmarshalJSON decodes the action as Brand Alert API does. Unknown actions are
+// decoded to Unknown. Use ClientParams.StrictActions to report them as errors.
+func (a *Action) UnmarshalJSON(b []byte) error {
+	str, err := unmarshalString(b)
+	if err != nil {
+		return err
+	}
+
+	*a, _ = ParseAction(str)
+
+	return nil
+}
+
+// Set sets the action from the string, so it can be used as flag.Value.
+// Unknown actions result in UnknownActionError.
+func (a *Action) Set(s string) error {
+	v, err := ParseAction(s)
+	if err != nil {
+		return err
+	}
+
+	*a = v
+
+	return nil
+}
+
 // DomainItem is a part of the Brand Alert API response.
 type DomainItem struct {
 	// DomainName is the full domain name.
 	DomainName string `json:"domainName"`
 
 	// Action is the related action. Possible actions: added | updated | dropped | discovered.
+	// Actions unknown to this library are decoded to Unknown.
 	Action Action `json:"action"`
 
 	// Date is the event date.
@@ -1,8 +1,13 @@
 package brandalert
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
+	"flag"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // TestTime tests the time conversion functions.
@@ -51,6 +56,109 @@ func TestTime(t *testing.T) {
 	}
 }
 
+// TestParseTime tests the tolerant time parsing.
+func TestParseTime(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name    string
+		loc     *time.Location
+		want    time.Time
+		wantErr string
+	}{
+		{
+			name: "2022-10-30",
+			want: time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "2022-10-30T15:04:05Z",
+			want: time.Date(2022, 10, 30, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "2022-10-30T15:04:05.123+03:00",
+			want: time.Date(2022, 10, 30, 15, 4, 5, 123000000, moscow),
+		},
+		{
+			name: "2022-10-30 15:04:05",
+			want: time.Date(2022, 10, 30, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "2022-10-30",
+			loc:  moscow,
+			want: time.Date(2022, 10, 30, 0, 0, 0, 0, moscow),
+		},
+		{
+			name: "",
+			want: time.Time{},
+		},
+		{
+			name:    "30.10.2022",
+			wantErr: `parsing time "30.10.2022" as "2006-01-02": cannot parse "30.10.2022" as "2006"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeInLocation(tt.name, tt.loc)
+			checkErr(t, err, tt.wantErr)
+
+			if !time.Time(got).Equal(tt.want) {
+				t.Errorf("ParseTimeInLocation() got = %v, want %v", time.Time(got), tt.want)
+			}
+		})
+	}
+}
+
+// TestTimeText tests the text and SQL conversion functions of Time.
+func TestTimeText(t *testing.T) {
+	var v Time
+
+	checkErr(t, v.UnmarshalText([]byte("2022-10-30T23:00:00Z")), "")
+	if v.String() != "2022-10-30" || v.IsZero() {
+		t.Errorf("UnmarshalText() got = %v", v)
+	}
+
+	bb, err := v.MarshalText()
+	checkErr(t, err, "")
+	if string(bb) != "2022-10-30" {
+		t.Errorf("MarshalText() got = %v", string(bb))
+	}
+
+	later, _ := ParseTime("2022-10-31")
+	if !v.Before(later) || v.After(later) || v.Equal(later) || !later.Equal(later.In(time.FixedZone("", 3600))) {
+		t.Errorf("comparison functions failed for %v and %v", v, later)
+	}
+
+	var _ sql.Scanner = &v
+	var _ driver.Valuer = v
+
+	tests := []struct {
+		src     interface{}
+		want    string
+		wantErr string
+	}{
+		{src: "2022-10-30", want: "2022-10-30"},
+		{src: []byte("2022-10-29"), want: "2022-10-29"},
+		{src: time.Date(2022, 10, 28, 0, 0, 0, 0, time.UTC), want: "2022-10-28"},
+		{src: nil, want: ""},
+		{src: 42, wantErr: "cannot scan int into Time"},
+	}
+	for _, tt := range tests {
+		var got Time
+
+		err := got.Scan(tt.src)
+		checkErr(t, err, tt.wantErr)
+		if got.String() != tt.want {
+			t.Errorf("Scan(%v) got = %v, want %v", tt.src, got, tt.want)
+		}
+
+		value, err := got.Value()
+		checkErr(t, err, "")
+		if (value == nil) != got.IsZero() {
+			t.Errorf("Value() got = %v for %v", value, got)
+		}
+	}
+}
+
 // TestMessages tests the Messages conversion functions.
 func TestMessages(t *testing.T) {
 	tests := []struct {
@@ -113,6 +221,98 @@ func TestMessages(t *testing.T) {
 	}
 }
 
+// TestAction tests the Action conversion functions.
+func TestAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Action
+		valid   bool
+		wantErr string
+	}{
+		{
+			name:  "added",
+			want:  Added,
+			valid: true,
+		},
+		{
+			name:  " Dropped",
+			want:  Dropped,
+			valid: true,
+		},
+		{
+			name:    "transferred",
+			want:    Unknown,
+			wantErr: `unknown action "transferred"`,
+		},
+		{
+			name:    "unknown",
+			want:    Unknown,
+			wantErr: `unknown action "unknown"`,
+		},
+		{
+			name:    "",
+			want:    Unknown,
+			wantErr: `unknown action ""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAction(tt.name)
+			checkErr(t, err, tt.wantErr)
+			if got != tt.want || got.Valid() != tt.valid {
+				t.Errorf("ParseAction() got = %v, want %v", got, tt.want)
+			}
+
+			var v Action
+			err = json.Unmarshal([]byte(`"`+tt.name+`"`), &v)
+			checkErr(t, err, "")
+			if v != tt.want {
+				t.Errorf("json.Unmarshal() got = %v, want %v", v, tt.want)
+			}
+
+			v = ""
+			err = v.UnmarshalText([]byte(tt.name))
+			checkErr(t, err, "")
+			if v != tt.want {
+				t.Errorf("UnmarshalText() got = %v, want %v", v, tt.want)
+			}
+
+			v = ""
+			err = v.Set(tt.name)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == "" && v != tt.want {
+				t.Errorf("Set() got = %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
+
+// TestActionText tests using Action in maps and flags.
+func TestActionText(t *testing.T) {
+	counts := map[Action]int{Added: 1, Dropped: 2}
+
+	bb, err := json.Marshal(counts)
+	checkErr(t, err, "")
+	if string(bb) != `{"added":1,"dropped":2}` {
+		t.Errorf("json.Marshal() got = %v", string(bb))
+	}
+
+	var decoded map[Action]int
+	checkErr(t, json.Unmarshal(bb, &decoded), "")
+	if !reflect.DeepEqual(decoded, counts) {
+		t.Errorf("json.Unmarshal() got = %v, want %v", decoded, counts)
+	}
+
+	var action Action
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&action, "action", "action")
+
+	checkErr(t, fs.Parse([]string{"-action", "Discovered"}), "")
+	if action != Discovered {
+		t.Errorf("flag got = %v, want %v", action, Discovered)
+	}
+}
+
 func checkErr(t *testing.T, err error, want string) {
 	if (err != nil || want != "") && (err == nil || err.Error() != want) {
 		t.Errorf("error = %v, wantErr %v", err, want)
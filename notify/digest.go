@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// actionOrder is the order of actions in digests. Other actions follow in
+// alphabetical order.
+var actionOrder = map[brandalert.Action]int{
+	brandalert.Added:      1,
+	brandalert.Discovered: 2,
+	brandalert.Updated:    3,
+	brandalert.Dropped:    4,
+}
+
+// ActionGroup is the list of domains with the same action.
+type ActionGroup struct {
+	// Action is the domain action.
+	Action brandalert.Action
+
+	// Items is the list of domains sorted by name.
+	Items []brandalert.DomainItem
+}
+
+// BrandDigest is the list of domains of the brand grouped by action.
+type BrandDigest struct {
+	// Brand is the brand name.
+	Brand string
+
+	// Total is the number of domains.
+	Total int
+
+	// Actions is the list of action groups.
+	Actions []ActionGroup
+}
+
+// Digest is the summary of notifications grouped by brand and action.
+type Digest struct {
+	// Time is the latest notification time.
+	Time time.Time
+
+	// Total is the number of domains of all brands.
+	Total int
+
+	// Brands is the list of brands sorted by name. Brands without domains are skipped.
+	Brands []BrandDigest
+}
+
+// NewDigest groups the notifications by brand and action. Notifications of
+// the same brand are merged.
+func NewDigest(ns []Notification) Digest {
+	var digest Digest
+
+	byBrand := make(map[string][]brandalert.DomainItem)
+
+	for _, n := range ns {
+		if n.Time.After(digest.Time) {
+			digest.Time = n.Time
+		}
+		if len(n.Items) > 0 {
+			byBrand[n.Brand] = append(byBrand[n.Brand], n.Items...)
+		}
+	}
+
+	for brand, items := range byBrand {
+		resp := brandalert.BrandAlertResponse{DomainsList: items}
+		bd := BrandDigest{Brand: brand, Total: len(items)}
+
+		for action, group := range resp.GroupByAction() {
+			bd.Actions = append(bd.Actions, ActionGroup{
+				Action: action,
+				Items:  (&brandalert.BrandAlertResponse{DomainsList: group}).SortByName().DomainsList,
+			})
+		}
+
+		sort.Slice(bd.Actions, func(i, j int) bool {
+			oi, oj := actionRank(bd.Actions[i].Action), actionRank(bd.Actions[j].Action)
+			if oi != oj {
+				return oi < oj
+			}
+			return bd.Actions[i].Action < bd.Actions[j].Action
+		})
+
+		digest.Total += bd.Total
+		digest.Brands = append(digest.Brands, bd)
+	}
+
+	sort.Slice(digest.Brands, func(i, j int) bool {
+		return digest.Brands[i].Brand < digest.Brands[j].Brand
+	})
+
+	if digest.Time.IsZero() {
+		digest.Time = time.Now()
+	}
+
+	return digest
+}
+
+// actionRank returns the position of the action in digests.
+func actionRank(action brandalert.Action) int {
+	if rank, ok := actionOrder[action]; ok {
+		return rank
+	}
+
+	return len(actionOrder) + 1
+}
+
+// digestFuncs is the list of functions available in digest templates.
+var digestFuncs = map[string]interface{}{
+	"title": func(a brandalert.Action) string {
+		s := string(a)
+		if s == "" {
+			return ""
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+}
+
+// textDigest is the plain text digest template.
+var textDigest = template.Must(template.New("text").Funcs(digestFuncs).Parse(
+	`Brand Alert digest: {{.Total}} new lookalike domain(s)
+{{range .Brands}}
+{{.Brand}} ({{.Total}})
+{{range .Actions}}
+  {{title .Action}}:
+{{range .Items}}    {{.DomainName}}{{with .Date.String}}  {{.}}{{end}}
+{{end}}{{end}}{{end}}`))
+
+// htmlDigest is the HTML digest template.
+var htmlDigest = htmltemplate.Must(htmltemplate.New("html").Funcs(digestFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif">
+<h2>Brand Alert digest: {{.Total}} new lookalike domain(s)</h2>
+{{range .Brands}}<h3>{{.Brand}} ({{.Total}})</h3>
+{{range .Actions}}<h4>{{title .Action}}</h4>
+<table cellpadding="4">
+{{range .Items}}<tr><td>{{.DomainName}}</td><td>{{.Date.String}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// Text returns the plain text representation of the digest.
+func (d Digest) Text() (string, error) {
+	var b bytes.Buffer
+	err := textDigest.Execute(&b, d)
+
+	return b.String(), err
+}
+
+// HTML returns the HTML representation of the digest.
+func (d Digest) HTML() (string, error) {
+	var b bytes.Buffer
+	err := htmlDigest.Execute(&b, d)
+
+	return b.String(), err
+}
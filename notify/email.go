@@ -0,0 +1,441 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StartTLS is the STARTTLS policy of the email sink.
+type StartTLS int
+
+// List of STARTTLS policies.
+const (
+	// StartTLSOpportunistic upgrades the connection if the server supports it.
+	StartTLSOpportunistic StartTLS = iota
+
+	// StartTLSRequired fails if the server doesn't support STARTTLS.
+	StartTLSRequired
+
+	// StartTLSDisabled never upgrades the connection.
+	StartTLSDisabled
+)
+
+// EmailParams is used to create email sinks. Address, From and recipients are mandatory.
+type EmailParams struct {
+	// Name is the sink name used in delivery records. Default: "smtp://<Address>".
+	Name string
+
+	// Address is the host:port of the SMTP server.
+	Address string
+
+	// Username and Password are used for PLAIN authentication if set.
+	// Credentials are only sent over TLS or to localhost.
+	Username, Password string
+
+	// StartTLS is the STARTTLS policy. Default: StartTLSOpportunistic.
+	StartTLS StartTLS
+
+	// TLSConfig is the TLS configuration. Default: verification against the server host name.
+	TLSConfig *tls.Config
+
+	// From is the sender address.
+	From string
+
+	// To is the list of recipients of the brands missing in Recipients.
+	To []string
+
+	// Recipients maps the brand to its recipients.
+	Recipients map[string][]string
+
+	// Subject is the subject prefix. Default: "Brand Alert digest".
+	Subject string
+
+	// Timeout limits a delivery attempt. Default: 1m.
+	Timeout time.Duration
+
+	// Retry is the retry policy. Network errors and 4xx replies are retried.
+	Retry Retry
+
+	// Recorder records the delivery statuses. It's optional.
+	Recorder Recorder
+}
+
+// Email is the sink sending notifications as HTML and plain text email digests.
+type Email struct {
+	params EmailParams
+	host   string
+}
+
+var _ Sink = &Email{}
+
+// NewEmail creates the email sink.
+func NewEmail(params EmailParams) (*Email, error) {
+	host, _, err := net.SplitHostPort(params.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address: %w", err)
+	}
+
+	if _, err := mail.ParseAddress(params.From); err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+
+	if len(params.To) == 0 && len(params.Recipients) == 0 {
+		return nil, errors.New("recipients can not be empty")
+	}
+
+	for _, list := range append([][]string{params.To}, recipientLists(params.Recipients)...) {
+		for _, addr := range list {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return nil, fmt.Errorf("invalid recipient %q: %w", addr, err)
+			}
+		}
+	}
+
+	if params.Name == "" {
+		params.Name = "smtp://" + params.Address
+	}
+	if params.Subject == "" {
+		params.Subject = "Brand Alert digest"
+	}
+	if params.Timeout == 0 {
+		params.Timeout = time.Minute
+	}
+	params.Retry = params.Retry.withDefaults()
+
+	return &Email{params: params, host: host}, nil
+}
+
+// Notify sends the digest of the notification.
+func (e *Email) Notify(ctx context.Context, n Notification) error {
+	return e.SendDigest(ctx, []Notification{n})
+}
+
+// SendDigest sends every recipient one digest with the domains of all brands
+// they are subscribed to. Recipients subscribed to the same brands share the
+// email. Brands without domains are skipped.
+func (e *Email) SendDigest(ctx context.Context, ns []Notification) error {
+	// subscriptions maps the recipient to the indexes of its notifications.
+	subscriptions := make(map[string][]int)
+
+	for i, n := range ns {
+		if len(n.Items) == 0 {
+			continue
+		}
+
+		for _, addr := range e.recipients(n.Brand) {
+			subscriptions[addr] = append(subscriptions[addr], i)
+		}
+	}
+
+	addrs := make([]string, 0, len(subscriptions))
+	for addr := range subscriptions {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	var (
+		keys       []string
+		recipients = make(map[string][]string)
+		groups     = make(map[string][]Notification)
+	)
+
+	for _, addr := range addrs {
+		key := fmt.Sprint(subscriptions[addr])
+
+		if _, ok := recipients[key]; !ok {
+			keys = append(keys, key)
+			for _, i := range subscriptions[addr] {
+				groups[key] = append(groups[key], ns[i])
+			}
+		}
+
+		recipients[key] = append(recipients[key], addr)
+	}
+
+	var errs []string
+
+	for _, key := range keys {
+		if err := e.send(ctx, recipients[key], NewDigest(groups[key])); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// recipients returns the sorted unique recipients of the brand.
+func (e *Email) recipients(brand string) []string {
+	list, ok := e.params.Recipients[brand]
+	if !ok {
+		list = e.params.To
+	}
+
+	seen := make(map[string]bool, len(list))
+	res := make([]string, 0, len(list))
+
+	for _, addr := range list {
+		if !seen[addr] {
+			seen[addr] = true
+			res = append(res, addr)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// send sends the digest to the recipients retrying failed attempts with backoff.
+func (e *Email) send(ctx context.Context, to []string, digest Digest) error {
+	brands := make([]string, 0, len(digest.Brands))
+	for _, b := range digest.Brands {
+		brands = append(brands, b.Brand)
+	}
+
+	delivery := Delivery{
+		Sink:  e.params.Name,
+		Brand: strings.Join(brands, ","),
+		Items: digest.Total,
+		Time:  time.Now(),
+	}
+
+	err := e.deliver(ctx, to, digest, &delivery)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	delivery.Duration = time.Since(delivery.Time)
+	if e.params.Recorder != nil {
+		e.params.Recorder.Record(delivery)
+	}
+
+	return err
+}
+
+// deliver builds the message and sends it updating the delivery status.
+func (e *Email) deliver(ctx context.Context, to []string, digest Digest, delivery *Delivery) error {
+	msg, err := e.Message(to, digest)
+	if err != nil {
+		return err
+	}
+
+	retry := e.params.Retry
+
+	for {
+		delivery.Attempts++
+
+		err = e.sendMail(ctx, to, msg)
+		if err == nil {
+			return nil
+		}
+
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) {
+			delivery.StatusCode = protoErr.Code
+		}
+
+		if !temporary(err) || ctx.Err() != nil || delivery.Attempts >= retry.Attempts {
+			return err
+		}
+
+		if err := sleep(ctx, retry.delay(delivery.Attempts)); err != nil {
+			return err
+		}
+	}
+}
+
+// Message returns the multipart/alternative message of the digest.
+func (e *Email) Message(to []string, digest Digest) ([]byte, error) {
+	text, err := digest.Text()
+	if err != nil {
+		return nil, fmt.Errorf("cannot render digest: %w", err)
+	}
+
+	html, err := digest.HTML()
+	if err != nil {
+		return nil, fmt.Errorf("cannot render digest: %w", err)
+	}
+
+	subject := fmt.Sprintf("%s: %d new lookalike domain(s)", e.params.Subject, digest.Total)
+	if len(digest.Brands) == 1 {
+		subject = fmt.Sprintf("%s: %d new lookalike domain(s) for %s", e.params.Subject, digest.Total, digest.Brands[0].Brand)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType, content string
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+		if _, err := io.WriteString(qp, part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+
+	headers := [][2]string{
+		{"From", e.params.From},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", digest.Time.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(e.params.From)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// sendMail sends the message over a new SMTP connection.
+func (e *Email) sendMail(ctx context.Context, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, e.params.Timeout)
+	defer cancel()
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", e.params.Address)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if e.params.StartTLS != StartTLSDisabled {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			config := &tls.Config{}
+			if e.params.TLSConfig != nil {
+				config = e.params.TLSConfig.Clone()
+			}
+			if config.ServerName == "" {
+				config.ServerName = e.host
+			}
+			if err := c.StartTLS(config); err != nil {
+				return err
+			}
+		} else if e.params.StartTLS == StartTLSRequired {
+			return errors.New("SMTP server doesn't support STARTTLS")
+		}
+	}
+
+	if e.params.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.params.Username, e.params.Password, e.host)); err != nil {
+			return err
+		}
+	}
+
+	from, _ := mail.ParseAddress(e.params.From)
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+
+	for _, addr := range to {
+		rcpt, _ := mail.ParseAddress(addr)
+		if err := c.Rcpt(rcpt.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// temporary reports whether the error is a network error or a 4xx SMTP reply.
+func temporary(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF)
+}
+
+// messageID returns a unique Message-ID in the domain of the sender.
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	return "<" + hex.EncodeToString(b[:]) + "@" + domain + ">"
+}
+
+// recipientLists returns the recipient lists of the brands.
+func recipientLists(recipients map[string][]string) [][]string {
+	lists := make([][]string, 0, len(recipients))
+	for _, list := range recipients {
+		lists = append(lists, list)
+	}
+
+	return lists
+}
@@ -0,0 +1,385 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// smtpMessage is the message received by smtpServer.
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+	TLS  bool
+	Auth bool
+}
+
+// smtpServer is the minimal in-process SMTP server.
+type smtpServer struct {
+	ln        net.Listener
+	tlsConfig *tls.Config
+	username  string
+	password  string
+
+	mu       sync.Mutex
+	failMail int
+	messages []smtpMessage
+}
+
+// newSMTPServer starts the SMTP server. If tlsConfig is not nil, STARTTLS is supported.
+func newSMTPServer(t *testing.T, tlsConfig *tls.Config) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &smtpServer{ln: ln, tlsConfig: tlsConfig}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	t.Cleanup(func() { ln.Close() })
+
+	return s
+}
+
+// serve handles the SMTP session.
+func (s *smtpServer) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	tp := textproto.NewConn(conn)
+
+	var msg smtpMessage
+
+	_ = tp.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, strings.SplitN(line, " ", 2)[0]))
+
+		switch cmd {
+		case "EHLO", "HELO":
+			lines := []string{"localhost"}
+			if s.tlsConfig != nil && !msg.TLS {
+				lines = append(lines, "STARTTLS")
+			}
+			if s.username != "" {
+				lines = append(lines, "AUTH PLAIN")
+			}
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				_ = tp.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			tp = textproto.NewConn(conn)
+			msg.TLS = true
+		case "AUTH":
+			creds, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			if string(creds) != "\x00"+s.username+"\x00"+s.password {
+				_ = tp.PrintfLine("535 authentication failed")
+				continue
+			}
+			msg.Auth = true
+			_ = tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			s.mu.Lock()
+			fail := s.failMail > 0
+			if fail {
+				s.failMail--
+			}
+			s.mu.Unlock()
+
+			if fail {
+				_ = tp.PrintfLine("451 try again later")
+				continue
+			}
+
+			msg.From = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+// received returns the received messages.
+func (s *smtpServer) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+// parts returns the decoded parts of the multipart message by content type.
+func parts(t *testing.T, data string) (*mail.Message, map[string]string) {
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("got content type %q, %v", mediaType, err)
+	}
+
+	res := make(map[string]string)
+
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		res[ct] = string(b)
+	}
+
+	return msg, res
+}
+
+var digestNotifications = []Notification{
+	{
+		Brand: "WhoisXML",
+		Items: []brandalert.DomainItem{
+			{DomainName: "whoisdodster.com", Action: brandalert.Dropped},
+			{DomainName: "whoisxml-login.com", Action: brandalert.Added, Date: brandalert.Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+			{DomainName: "batchwhois.com", Action: brandalert.Added},
+		},
+		Time: time.Date(2022, 11, 1, 8, 0, 0, 0, time.UTC),
+	},
+	{
+		Brand: "<Acme>",
+		Items: []brandalert.DomainItem{{DomainName: "acme-support.com", Action: brandalert.Added}},
+		Time:  time.Date(2022, 11, 1, 9, 0, 0, 0, time.UTC),
+	},
+	{
+		Brand: "Globex",
+		Items: []brandalert.DomainItem{{DomainName: "globex.tk", Action: brandalert.Updated}},
+	},
+	{
+		Brand: "Empty",
+	},
+}
+
+// TestDigest tests the digest grouping and rendering.
+func TestDigest(t *testing.T) {
+	digest := NewDigest(digestNotifications[:2])
+
+	if digest.Total != 4 || len(digest.Brands) != 2 || !digest.Time.Equal(digestNotifications[1].Time) {
+		t.Fatalf("NewDigest() got = %+v", digest)
+	}
+
+	text, err := digest.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Brand Alert digest: 4 new lookalike domain(s)
+
+<Acme> (1)
+
+  Added:
+    acme-support.com
+
+WhoisXML (3)
+
+  Added:
+    batchwhois.com
+    whoisxml-login.com  2022-10-30
+
+  Dropped:
+    whoisdodster.com
+`
+	if text != want {
+		t.Errorf("Text() got = %q, want %q", text, want)
+	}
+
+	html, err := digest.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(html, "<h3>&lt;Acme&gt; (1)</h3>") || !strings.Contains(html, "<td>whoisxml-login.com</td><td>2022-10-30</td>") {
+		t.Errorf("HTML() got = %s", html)
+	}
+}
+
+// TestEmail tests sending digests with STARTTLS, authentication and per-brand recipients.
+func TestEmail(t *testing.T) {
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	defer ts.Close()
+
+	server := newSMTPServer(t, &tls.Config{Certificates: ts.TLS.Certificates})
+	server.username, server.password = "alerts", "secret"
+	server.failMail = 1
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	log := NewLog(0)
+
+	sink, err := NewEmail(EmailParams{
+		Address:   server.ln.Addr().String(),
+		Username:  "alerts",
+		Password:  "secret",
+		StartTLS:  StartTLSRequired,
+		TLSConfig: &tls.Config{RootCAs: pool},
+		From:      "Brand Alert <alerts@example.com>",
+		To:        []string{"legal@example.com"},
+		Recipients: map[string][]string{
+			"Globex": {"globex@example.com", "legal@example.com"},
+		},
+		Retry:    testRetry,
+		Recorder: log,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.SendDigest(context.Background(), digestNotifications); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := server.received()
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+
+	for _, m := range msgs {
+		if !m.TLS || !m.Auth || m.From != "alerts@example.com" {
+			t.Errorf("got message %+v", m)
+		}
+	}
+
+	// legal@example.com gets all brands in one email.
+	if got := strings.Join(msgs[0].To, ","); got != "globex@example.com" {
+		t.Errorf("got recipients %v", got)
+	}
+
+	header, body := parts(t, msgs[1].Data)
+
+	if got := strings.Join(msgs[1].To, ","); got != "legal@example.com" {
+		t.Errorf("got recipients %v", got)
+	}
+
+	subject, _ := new(mime.WordDecoder).DecodeHeader(header.Header.Get("Subject"))
+	if subject != "Brand Alert digest: 5 new lookalike domain(s)" {
+		t.Errorf("got subject %q", subject)
+	}
+
+	if !strings.Contains(body["text/plain"], "whoisxml-login.com  2022-10-30") || !strings.Contains(body["text/html"], "<h3>WhoisXML (3)</h3>") {
+		t.Errorf("got body %v", body)
+	}
+
+	deliveries := log.Deliveries()
+	if len(deliveries) != 2 || deliveries[0].Attempts != 2 || deliveries[0].Brand != "Globex" ||
+		deliveries[1].Attempts != 1 || deliveries[1].Brand != "<Acme>,Globex,WhoisXML" || deliveries[1].Items != 5 {
+		t.Errorf("got deliveries %+v", deliveries)
+	}
+}
+
+// TestEmailErrors tests the email sink errors.
+func TestEmailErrors(t *testing.T) {
+	server := newSMTPServer(t, nil)
+
+	tests := []struct {
+		name    string
+		params  EmailParams
+		wantErr string
+	}{
+		{
+			name:    "no recipients",
+			params:  EmailParams{Address: "localhost:25", From: "alerts@example.com"},
+			wantErr: "recipients can not be empty",
+		},
+		{
+			name:    "invalid recipient",
+			params:  EmailParams{Address: "localhost:25", From: "alerts@example.com", Recipients: map[string][]string{"a": {"legal"}}},
+			wantErr: `invalid recipient "legal": mail: missing '@' or angle-addr`,
+		},
+		{
+			name:    "invalid address",
+			params:  EmailParams{Address: "localhost", From: "alerts@example.com", To: []string{"legal@example.com"}},
+			wantErr: "invalid SMTP address: address localhost: missing port in address",
+		},
+		{
+			name:    "STARTTLS required",
+			params:  EmailParams{Address: server.ln.Addr().String(), From: "alerts@example.com", To: []string{"legal@example.com"}, StartTLS: StartTLSRequired},
+			wantErr: "SMTP server doesn't support STARTTLS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := NewEmail(tt.params)
+			if err == nil {
+				err = sink.Notify(context.Background(), digestNotifications[0])
+			}
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package notify pushes new Brand Alert API domains to people and systems,
+// e.g. chat, ticketing systems or email, and records every delivery attempt.
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// Notification is the batch of new domains found for the brand.
+type Notification struct {
+	// Brand is the brand name.
+	Brand string `json:"brand"`
+
+	// Items is the list of new domains.
+	Items []brandalert.DomainItem `json:"domains"`
+
+	// Time is the time the domains were found. Default: the current time.
+	Time time.Time `json:"time"`
+}
+
+// Count returns the number of domains.
+func (n Notification) Count() int {
+	return len(n.Items)
+}
+
+// Sink delivers notifications.
+type Sink interface {
+	// Notify delivers the notification.
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Delivery is the delivery status of the notification.
+type Delivery struct {
+	// Sink is the name of the sink.
+	Sink string `json:"sink"`
+
+	// Brand is the brand of the notification.
+	Brand string `json:"brand"`
+
+	// Items is the number of delivered domains.
+	Items int `json:"items"`
+
+	// Attempts is the number of delivery attempts.
+	Attempts int `json:"attempts"`
+
+	// StatusCode is the last response status code, if any.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Error is the last error message. It's empty if delivered successfully.
+	Error string `json:"error,omitempty"`
+
+	// Time is the time the delivery was started.
+	Time time.Time `json:"time"`
+
+	// Duration is the time spent on delivery including retries.
+	Duration time.Duration `json:"duration"`
+}
+
+// OK reports whether the notification was delivered.
+func (d Delivery) OK() bool {
+	return d.Error == ""
+}
+
+// Recorder records delivery statuses.
+type Recorder interface {
+	Record(d Delivery)
+}
+
+// Log is the in-memory Recorder keeping the latest deliveries.
+// It's safe for concurrent use.
+type Log struct {
+	mu         sync.Mutex
+	max        int
+	deliveries []Delivery
+}
+
+// NewLog creates Log keeping up to max latest deliveries. Zero means no limit.
+func NewLog(max int) *Log {
+	return &Log{max: max}
+}
+
+// Record records the delivery.
+func (l *Log) Record(d Delivery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deliveries = append(l.deliveries, d)
+	if l.max > 0 && len(l.deliveries) > l.max {
+		l.deliveries = append(l.deliveries[:0:0], l.deliveries[len(l.deliveries)-l.max:]...)
+	}
+}
+
+// Deliveries returns the recorded deliveries from the oldest to the latest.
+func (l *Log) Deliveries() []Delivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]Delivery(nil), l.deliveries...)
+}
+
+// Retry is the retry policy with exponential backoff.
+type Retry struct {
+	// Attempts is the maximum number of attempts. Default: 3.
+	Attempts int
+
+	// Backoff is the delay before the second attempt. Every next delay is
+	// doubled. Default: 1s.
+	Backoff time.Duration
+
+	// MaxBackoff limits the delay. Default: 30s.
+	MaxBackoff time.Duration
+}
+
+// withDefaults returns the policy with the default values set.
+func (r Retry) withDefaults() Retry {
+	if r.Attempts <= 0 {
+		r.Attempts = 3
+	}
+	if r.Backoff <= 0 {
+		r.Backoff = time.Second
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 30 * time.Second
+	}
+
+	return r
+}
+
+// delay returns the delay before the attempt following the given one.
+func (r Retry) delay(attempt int) time.Duration {
+	d := r.Backoff
+	for i := 1; i < attempt && d < r.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+
+	return d
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
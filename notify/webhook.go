@@ -0,0 +1,298 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Names of the built-in webhook templates.
+const (
+	// TemplateJSON is the generic JSON payload with the notification fields.
+	TemplateJSON = "json"
+
+	// TemplateSlack is the Slack incoming webhook payload.
+	TemplateSlack = "slack"
+
+	// TemplateTeams is the Microsoft Teams incoming webhook message card.
+	TemplateTeams = "teams"
+)
+
+// DefaultSignatureHeader is the header carrying the HMAC-SHA256 signature of the body.
+const DefaultSignatureHeader = "X-Brand-Alert-Signature"
+
+// templates is the list of built-in webhook templates.
+var templates = map[string]string{
+	TemplateJSON: `{"brand":{{json .Brand}},"time":{{json .Time}},"count":{{.Count}},"domains":{{json .Items}}}`,
+
+	TemplateSlack: `{"text":"{{jsonEscape (printf "%d new lookalike domain(s) for %s" .Count .Brand)}}
+{{- range .Items}}\n• {{jsonEscape .DomainName}} ({{.Action}}{{with .Date.String}}, {{.}}{{end}}){{end}}"}`,
+
+	TemplateTeams: `{"@type":"MessageCard","@context":"https://schema.org/extensions",
+"summary":"{{jsonEscape (printf "%d new lookalike domain(s) for %s" .Count .Brand)}}",
+"title":"Brand Alert: {{jsonEscape .Brand}}",
+"text":"{{range $i, $item := .Items}}{{if $i}}\n\n{{end}}**{{jsonEscape .DomainName}}** {{.Action}}{{with .Date.String}} {{.}}{{end}}{{end}}"}`,
+}
+
+// funcs is the list of functions available in webhook templates.
+var funcs = template.FuncMap{
+	// json returns the JSON encoding of the value.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+
+	// jsonEscape returns the string escaped for a JSON string literal.
+	"jsonEscape": func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b[1 : len(b)-1])
+	},
+
+	// date formats the time with the layout.
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+}
+
+// Template parses the webhook template text. The template is executed with
+// Notification and can use json, jsonEscape and date functions.
+func Template(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(funcs).Parse(text)
+}
+
+// WebhookParams is used to create webhook sinks. Only URL is mandatory.
+type WebhookParams struct {
+	// Name is the sink name used in delivery records. Default: the URL host.
+	Name string
+
+	// URL is the webhook URL.
+	URL string
+
+	// Template is the name of the built-in payload template: TemplateJSON
+	// (default), TemplateSlack or TemplateTeams. It's ignored if Body is set.
+	Template string
+
+	// Body is the custom payload template, see the Template function.
+	Body *template.Template
+
+	// ContentType is the payload content type. Default: application/json.
+	// JSON payloads are validated before sending.
+	ContentType string
+
+	// Headers is the list of additional request headers.
+	Headers map[string]string
+
+	// Secret is the HMAC-SHA256 key. If it's set, the hex encoded signature
+	// of the body is sent as "sha256=<signature>" in SignatureHeader.
+	Secret string
+
+	// SignatureHeader is the signature header name. Default: DefaultSignatureHeader.
+	SignatureHeader string
+
+	// HTTPClient is the client used to send requests. Default: a client with a 30s timeout.
+	HTTPClient *http.Client
+
+	// Retry is the retry policy. Network errors, 429 and 5xx responses are retried.
+	Retry Retry
+
+	// Recorder records the delivery statuses. It's optional.
+	Recorder Recorder
+}
+
+// Webhook is the sink posting notifications to the webhook URL.
+type Webhook struct {
+	params WebhookParams
+	body   *template.Template
+}
+
+var _ Sink = &Webhook{}
+
+// NewWebhook creates the webhook sink.
+func NewWebhook(params WebhookParams) (*Webhook, error) {
+	u, err := url.Parse(params.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook URL: unsupported scheme %q", u.Scheme)
+	}
+
+	body := params.Body
+	if body == nil {
+		name := params.Template
+		if name == "" {
+			name = TemplateJSON
+		}
+
+		text, ok := templates[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown template %q", name)
+		}
+
+		body = template.Must(Template(text))
+	}
+
+	if params.Name == "" {
+		params.Name = u.Host
+	}
+	if params.ContentType == "" {
+		params.ContentType = "application/json"
+	}
+	if params.SignatureHeader == "" {
+		params.SignatureHeader = DefaultSignatureHeader
+	}
+	if params.HTTPClient == nil {
+		params.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	params.Retry = params.Retry.withDefaults()
+
+	return &Webhook{params: params, body: body}, nil
+}
+
+// Render returns the payload of the notification.
+func (w *Webhook) Render(n Notification) ([]byte, error) {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+
+	var b bytes.Buffer
+	if err := w.body.Execute(&b, n); err != nil {
+		return nil, fmt.Errorf("cannot render payload: %w", err)
+	}
+
+	if strings.HasPrefix(w.params.ContentType, "application/json") && !json.Valid(b.Bytes()) {
+		return nil, errors.New("cannot render payload: invalid JSON")
+	}
+
+	return b.Bytes(), nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify posts the notification retrying failed attempts with backoff.
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	delivery := Delivery{
+		Sink:  w.params.Name,
+		Brand: n.Brand,
+		Items: n.Count(),
+		Time:  time.Now(),
+	}
+
+	err := w.notify(ctx, n, &delivery)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	delivery.Duration = time.Since(delivery.Time)
+	if w.params.Recorder != nil {
+		w.params.Recorder.Record(delivery)
+	}
+
+	return err
+}
+
+// notify posts the notification updating the delivery status.
+func (w *Webhook) notify(ctx context.Context, n Notification, delivery *Delivery) error {
+	body, err := w.Render(n)
+	if err != nil {
+		return err
+	}
+
+	retry := w.params.Retry
+
+	for {
+		delivery.Attempts++
+
+		var wait time.Duration
+		wait, err = w.post(ctx, body, delivery)
+		if err == nil {
+			return nil
+		}
+
+		if wait < 0 || delivery.Attempts >= retry.Attempts {
+			return err
+		}
+
+		if d := retry.delay(delivery.Attempts); d > wait {
+			wait = d
+		}
+		if wait > retry.MaxBackoff {
+			wait = retry.MaxBackoff
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// post sends the body once. It returns a negative wait duration if the
+// error is permanent, or the delay requested by the server otherwise.
+func (w *Webhook) post(ctx context.Context, body []byte, delivery *Delivery) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.params.URL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+
+	req.Header.Set("Content-Type", w.params.ContentType)
+	for k, v := range w.params.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.params.Secret != "" {
+		req.Header.Set(w.params.SignatureHeader, "sha256="+Sign(w.params.Secret, body))
+	}
+
+	resp, err := w.params.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+
+	err = fmt.Errorf("webhook responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp.Header.Get("Retry-After")), err
+	case resp.StatusCode >= 500:
+		return 0, err
+	}
+
+	return -1, err
+}
+
+// retryAfter returns the delay of the Retry-After header in seconds.
+func retryAfter(v string) time.Duration {
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
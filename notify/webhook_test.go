@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+var testNotification = Notification{
+	Brand: `Whois"XML`,
+	Items: []brandalert.DomainItem{
+		{DomainName: "whoisdodster.com", Action: brandalert.Added, Date: brandalert.Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+		{DomainName: "batchwhois.com", Action: brandalert.Dropped},
+	},
+	Time: time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC),
+}
+
+var testRetry = Retry{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// TestWebhookTemplates tests rendering of the built-in templates.
+func TestWebhookTemplates(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{
+			template: TemplateJSON,
+			want: `{"brand":"Whois\"XML","time":"2022-11-01T12:00:00Z","count":2,"domains":[` +
+				`{"domainName":"whoisdodster.com","action":"added","date":"2022-10-30"},` +
+				`{"domainName":"batchwhois.com","action":"dropped","date":""}]}`,
+		},
+		{
+			template: TemplateSlack,
+			want:     `{"text":"2 new lookalike domain(s) for Whois\"XML\n• whoisdodster.com (added, 2022-10-30)\n• batchwhois.com (dropped)"}`,
+		},
+		{
+			template: TemplateTeams,
+			want: `{"@type":"MessageCard","@context":"https://schema.org/extensions",
+"summary":"2 new lookalike domain(s) for Whois\"XML",
+"title":"Brand Alert: Whois\"XML",
+"text":"**whoisdodster.com** added 2022-10-30\n\n**batchwhois.com** dropped"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			w, err := NewWebhook(WebhookParams{URL: "https://hooks.example.com/x", Template: tt.template})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := w.Render(testNotification)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Render() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWebhookNotify tests delivery with signing and retries.
+func TestWebhookNotify(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if got := r.Header.Get(DefaultSignatureHeader); got != "sha256="+Sign("secret", body) {
+			t.Errorf("got signature %q", got)
+		}
+		if r.Header.Get("X-Tenant") != "acme" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got headers %v", r.Header)
+		}
+
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			var n Notification
+			if err := json.Unmarshal(body, &n); err != nil || n.Count() != 2 {
+				t.Errorf("got body %s", body)
+			}
+		}
+	}))
+	defer server.Close()
+
+	log := NewLog(10)
+
+	w, err := NewWebhook(WebhookParams{
+		Name:     "tickets",
+		URL:      server.URL,
+		Secret:   "secret",
+		Headers:  map[string]string{"X-Tenant": "acme"},
+		Retry:    testRetry,
+		Recorder: log,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Notify(context.Background(), testNotification); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := log.Deliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+
+	d := deliveries[0]
+	if !d.OK() || d.Sink != "tickets" || d.Attempts != 3 || d.StatusCode != http.StatusOK || d.Items != 2 {
+		t.Errorf("got delivery %+v", d)
+	}
+}
+
+// TestWebhookErrors tests permanent and exhausted delivery errors.
+func TestWebhookErrors(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		if strings.HasSuffix(r.URL.Path, "/bad") {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path      string
+		wantCalls int32
+		wantErr   string
+	}{
+		{
+			path:      "/bad",
+			wantCalls: 1,
+			wantErr:   "webhook responded with 400 Bad Request: invalid payload",
+		},
+		{
+			path:      "/down",
+			wantCalls: 3,
+			wantErr:   "webhook responded with 502 Bad Gateway: down",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+
+			log := NewLog(0)
+
+			w, err := NewWebhook(WebhookParams{URL: server.URL + tt.path, Retry: testRetry, Recorder: log})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.Notify(context.Background(), testNotification)
+			checkErr(t, err, tt.wantErr)
+
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", got, tt.wantCalls)
+			}
+
+			if d := log.Deliveries(); len(d) != 1 || d[0].OK() || d[0].Error != tt.wantErr {
+				t.Errorf("got deliveries %+v", d)
+			}
+		})
+	}
+
+	_, err := NewWebhook(WebhookParams{URL: "ftp://example.com"})
+	checkErr(t, err, `invalid webhook URL: unsupported scheme "ftp"`)
+
+	_, err = NewWebhook(WebhookParams{URL: "https://example.com", Template: "discord"})
+	checkErr(t, err, `unknown template "discord"`)
+
+	body, err := Template(`{"brand":{{.Brand}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWebhook(WebhookParams{URL: server.URL, Body: body})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = w.Render(testNotification)
+	checkErr(t, err, "cannot render payload: invalid JSON")
+}
+
+// TestLog tests the Log recorder limit.
+func TestLog(t *testing.T) {
+	log := NewLog(2)
+
+	for _, brand := range []string{"a", "b", "c"} {
+		log.Record(Delivery{Brand: brand})
+	}
+
+	d := log.Deliveries()
+	if len(d) != 2 || d[0].Brand != "b" || d[1].Brand != "c" {
+		t.Errorf("Deliveries() got = %+v", d)
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
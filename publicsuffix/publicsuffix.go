@@ -0,0 +1,259 @@
+// Package publicsuffix splits domain names into the subdomain, the
+// registrable domain and the public suffix using the Public Suffix List.
+//
+// A copy of the list is embedded into the package. Run "go generate" to
+// update it, or load a fresh copy at runtime with Load and SetDefault.
+package publicsuffix
+
+//go:generate go run update.go
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed" // the embedded Public Suffix List
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/whois-api-llc/brand-alert-go/internal/punycode"
+)
+
+//go:embed public_suffix_list.dat
+var embeddedList []byte
+
+// ruleType is the type of the Public Suffix List rule.
+type ruleType uint8
+
+const (
+	normalRule ruleType = 1 << iota
+	wildcardRule
+	exceptionRule
+)
+
+// rule is the Public Suffix List rule.
+type rule struct {
+	types ruleType
+	icann bool
+}
+
+// List is the parsed Public Suffix List.
+type List struct {
+	rules map[string]rule
+}
+
+// Parts is the domain name split into parts.
+type Parts struct {
+	// Subdomain is the part of the domain name left of the registrable domain.
+	Subdomain string `json:"subdomain,omitempty"`
+
+	// RegistrableDomain is the public suffix plus one label, e.g. "example.co.uk".
+	// It is empty if the domain name is a public suffix itself.
+	RegistrableDomain string `json:"registrableDomain,omitempty"`
+
+	// PublicSuffix is the public suffix, e.g. "co.uk".
+	PublicSuffix string `json:"publicSuffix"`
+
+	// ICANN is true if the public suffix is managed by ICANN, false if it
+	// is a private domain or no rule matched.
+	ICANN bool `json:"icann"`
+}
+
+// Parse parses the Public Suffix List in its original format.
+func Parse(r io.Reader) (*List, error) {
+	list := &List{
+		rules: make(map[string]rule),
+	}
+
+	icann := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.Contains(line, "===BEGIN ICANN DOMAINS==="):
+			icann = true
+			continue
+		case strings.Contains(line, "===END ICANN DOMAINS==="):
+			icann = false
+			continue
+		case line == "" || strings.HasPrefix(line, "//"):
+			continue
+		}
+
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			line = line[:i]
+		}
+
+		typ := normalRule
+		switch {
+		case strings.HasPrefix(line, "!"):
+			typ, line = exceptionRule, line[1:]
+		case strings.HasPrefix(line, "*."):
+			typ, line = wildcardRule, line[2:]
+		}
+
+		name, err := punycode.ToASCII(strings.ToLower(line))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse rule %q: %w", line, err)
+		}
+
+		r := list.rules[name]
+		r.types |= typ
+		r.icann = r.icann || icann
+		list.rules[name] = r
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read list: %w", err)
+	}
+
+	if len(list.rules) == 0 {
+		return nil, fmt.Errorf("cannot parse list: no rules found")
+	}
+
+	return list, nil
+}
+
+// Load loads the Public Suffix List from the file.
+func Load(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open list: %w", err)
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Len returns the number of rules in the list.
+func (l *List) Len() int {
+	return len(l.rules)
+}
+
+// Split splits the domain name into parts. The domain name is converted to
+// lower case and its Unicode labels are converted to Punycode.
+func (l *List) Split(domain string) Parts {
+	domain = normalize(domain)
+	if domain == "" {
+		return Parts{}
+	}
+
+	labels := strings.Split(domain, ".")
+
+	// The default "*" rule: the last label is the public suffix.
+	suffixLen, icann := 1, false
+
+	for i := len(labels) - 1; i >= 0; i-- {
+		name := strings.Join(labels[i:], ".")
+		n := len(labels) - i
+
+		r, ok := l.rules[name]
+		if !ok {
+			continue
+		}
+
+		if r.types&exceptionRule != 0 {
+			suffixLen, icann = n-1, r.icann
+			break
+		}
+
+		if r.types&normalRule != 0 && n >= suffixLen {
+			suffixLen, icann = n, r.icann
+		}
+
+		if r.types&wildcardRule != 0 && i > 0 && n+1 >= suffixLen {
+			if er, ok := l.rules[labels[i-1]+"."+name]; ok && er.types&exceptionRule != 0 {
+				continue
+			}
+			suffixLen, icann = n+1, r.icann
+		}
+	}
+
+	parts := Parts{
+		PublicSuffix: strings.Join(labels[len(labels)-suffixLen:], "."),
+		ICANN:        icann,
+	}
+
+	if len(labels) > suffixLen {
+		parts.RegistrableDomain = strings.Join(labels[len(labels)-suffixLen-1:], ".")
+		parts.Subdomain = strings.Join(labels[:len(labels)-suffixLen-1], ".")
+	}
+
+	return parts
+}
+
+// PublicSuffix returns the public suffix of the domain name.
+func (l *List) PublicSuffix(domain string) string {
+	return l.Split(domain).PublicSuffix
+}
+
+// RegistrableDomain returns the registrable domain of the domain name, or an
+// empty string if the domain name is a public suffix.
+func (l *List) RegistrableDomain(domain string) string {
+	return l.Split(domain).RegistrableDomain
+}
+
+// defaultList is the list set by SetDefault.
+var defaultList atomic.Value
+
+var (
+	embeddedOnce sync.Once
+	embedded     *List
+)
+
+// Default returns the list used by the package-level functions.
+// Unless replaced by SetDefault, it is the embedded copy of the Public Suffix List.
+func Default() *List {
+	if l, ok := defaultList.Load().(*List); ok {
+		return l
+	}
+
+	embeddedOnce.Do(func() {
+		var err error
+		if embedded, err = Parse(bytes.NewReader(embeddedList)); err != nil {
+			panic(err)
+		}
+	})
+
+	return embedded
+}
+
+// SetDefault replaces the list used by the package-level functions,
+// e.g. with a fresh copy downloaded at runtime.
+func SetDefault(l *List) {
+	if l == nil {
+		panic("publicsuffix: nil list")
+	}
+
+	defaultList.Store(l)
+}
+
+// Split splits the domain name into parts using the default list.
+func Split(domain string) Parts {
+	return Default().Split(domain)
+}
+
+// PublicSuffix returns the public suffix of the domain name using the default list.
+func PublicSuffix(domain string) string {
+	return Default().PublicSuffix(domain)
+}
+
+// RegistrableDomain returns the registrable domain of the domain name using the default list.
+func RegistrableDomain(domain string) string {
+	return Default().RegistrableDomain(domain)
+}
+
+// normalize returns the lower case ASCII domain name without the trailing dot.
+func normalize(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+
+	if ascii, err := punycode.ToASCII(domain); err == nil {
+		return ascii
+	}
+
+	return domain
+}
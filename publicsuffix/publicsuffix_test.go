@@ -0,0 +1,108 @@
+package publicsuffix
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSplit tests the Split function with the embedded list.
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		want Parts
+	}{
+		{
+			name: "",
+			want: Parts{},
+		},
+		{
+			name: "com",
+			want: Parts{PublicSuffix: "com", ICANN: true},
+		},
+		{
+			name: "whoisxmlapi.com",
+			want: Parts{RegistrableDomain: "whoisxmlapi.com", PublicSuffix: "com", ICANN: true},
+		},
+		{
+			name: "Brand-Alert.WhoisXMLAPI.com.",
+			want: Parts{Subdomain: "brand-alert", RegistrableDomain: "whoisxmlapi.com", PublicSuffix: "com", ICANN: true},
+		},
+		{
+			name: "a.b.example.co.uk",
+			want: Parts{Subdomain: "a.b", RegistrableDomain: "example.co.uk", PublicSuffix: "co.uk", ICANN: true},
+		},
+		{
+			name: "test.ck",
+			want: Parts{PublicSuffix: "test.ck", ICANN: true},
+		},
+		{
+			name: "b.test.ck",
+			want: Parts{RegistrableDomain: "b.test.ck", PublicSuffix: "test.ck", ICANN: true},
+		},
+		{
+			name: "www.ck",
+			want: Parts{RegistrableDomain: "www.ck", PublicSuffix: "ck", ICANN: true},
+		},
+		{
+			name: "a.www.ck",
+			want: Parts{Subdomain: "a", RegistrableDomain: "www.ck", PublicSuffix: "ck", ICANN: true},
+		},
+		{
+			name: "whois.github.io",
+			want: Parts{RegistrableDomain: "whois.github.io", PublicSuffix: "github.io"},
+		},
+		{
+			name: "example.unknowntld",
+			want: Parts{RegistrableDomain: "example.unknowntld", PublicSuffix: "unknowntld"},
+		},
+		{
+			name: "食狮.公司.cn",
+			want: Parts{RegistrableDomain: "xn--85x722f.xn--55qx5d.cn", PublicSuffix: "xn--55qx5d.cn", ICANN: true},
+		},
+		{
+			name: "shishi.xn--fiqs8s",
+			want: Parts{RegistrableDomain: "shishi.xn--fiqs8s", PublicSuffix: "xn--fiqs8s", ICANN: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.name); got != tt.want {
+				t.Errorf("Split() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSetDefault tests replacing the default list.
+func TestSetDefault(t *testing.T) {
+	const list = `// ===BEGIN ICANN DOMAINS===
+com
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+whoisxmlapi.com
+// ===END PRIVATE DOMAINS===
+`
+
+	l, err := Parse(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	embedded := Default()
+	defer SetDefault(embedded)
+
+	SetDefault(l)
+
+	want := Parts{RegistrableDomain: "brand-alert.whoisxmlapi.com", PublicSuffix: "whoisxmlapi.com"}
+	if got := Split("brand-alert.whoisxmlapi.com"); got != want {
+		t.Errorf("Split() got = %+v, want %+v", got, want)
+	}
+
+	if got := RegistrableDomain("www.example.co.uk"); got != "co.uk" {
+		t.Errorf("RegistrableDomain() got = %v, want %v", got, "co.uk")
+	}
+
+	if _, err := Parse(strings.NewReader("// comment only\n")); err == nil {
+		t.Errorf("Parse() expected error for empty list")
+	}
+}
@@ -0,0 +1,48 @@
+//go:build ignore
+// +build ignore
+
+// This program downloads the latest Public Suffix List to be embedded into the package.
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/whois-api-llc/brand-alert-go/publicsuffix"
+)
+
+const (
+	listURL  = "https://publicsuffix.org/list/public_suffix_list.dat"
+	listFile = "public_suffix_list.dat"
+)
+
+func main() {
+	resp, err := http.Get(listURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("cannot download list: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	list, err := publicsuffix.Parse(bytes.NewReader(data))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(listFile, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("%s updated: %d rules", listFile, list.Len())
+}
@@ -0,0 +1,211 @@
+package brandalert
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TLD returns the last label of the domain name in lower case.
+func (d DomainItem) TLD() string {
+	name := strings.TrimSuffix(strings.ToLower(d.DomainName), ".")
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+// Filter returns a copy of the response with the domains matching the predicate.
+// DomainsCount of the copy is the number of domains left.
+func (r *BrandAlertResponse) Filter(match func(DomainItem) bool) *BrandAlertResponse {
+	res := &BrandAlertResponse{
+		DomainsList: make([]DomainItem, 0, len(r.DomainsList)),
+	}
+
+	for _, item := range r.DomainsList {
+		if match(item) {
+			res.DomainsList = append(res.DomainsList, item)
+		}
+	}
+
+	res.DomainsCount = len(res.DomainsList)
+
+	return res
+}
+
+// FilterAction returns a copy of the response with the domains having one of the actions.
+func (r *BrandAlertResponse) FilterAction(actions ...Action) *BrandAlertResponse {
+	set := make(map[Action]bool, len(actions))
+	for _, action := range actions {
+		set[action] = true
+	}
+
+	return r.Filter(func(item DomainItem) bool {
+		return set[item.Action]
+	})
+}
+
+// FilterDateRange returns a copy of the response with the domains dated
+// between from and to inclusive. A zero bound means no limit.
+func (r *BrandAlertResponse) FilterDateRange(from, to time.Time) *BrandAlertResponse {
+	return r.Filter(func(item DomainItem) bool {
+		date := time.Time(item.Date)
+		return (from.IsZero() || !date.Before(from)) && (to.IsZero() || !date.After(to))
+	})
+}
+
+// FilterTLD returns a copy of the response with the domains having one of the TLDs.
+func (r *BrandAlertResponse) FilterTLD(tlds ...string) *BrandAlertResponse {
+	set := toSet(tlds, func(s string) string {
+		return strings.TrimPrefix(strings.ToLower(s), ".")
+	})
+
+	return r.Filter(func(item DomainItem) bool {
+		return set[item.TLD()]
+	})
+}
+
+// FilterRegexp returns a copy of the response with the domain names matching the regular expression.
+func (r *BrandAlertResponse) FilterRegexp(re *regexp.Regexp) *BrandAlertResponse {
+	return r.Filter(func(item DomainItem) bool {
+		return re.MatchString(item.DomainName)
+	})
+}
+
+// sorted returns a copy of the response with the domains sorted by less.
+func (r *BrandAlertResponse) sorted(less func(a, b DomainItem) bool) *BrandAlertResponse {
+	res := &BrandAlertResponse{
+		DomainsList:  append([]DomainItem(nil), r.DomainsList...),
+		DomainsCount: r.DomainsCount,
+	}
+
+	sort.SliceStable(res.DomainsList, func(i, j int) bool {
+		return less(res.DomainsList[i], res.DomainsList[j])
+	})
+
+	return res
+}
+
+// SortByDate returns a copy of the response with the domains sorted by date, oldest first.
+// Domains of the same date are sorted by name.
+func (r *BrandAlertResponse) SortByDate() *BrandAlertResponse {
+	return r.sorted(func(a, b DomainItem) bool {
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.DomainName < b.DomainName
+	})
+}
+
+// SortByName returns a copy of the response with the domains sorted by name.
+func (r *BrandAlertResponse) SortByName() *BrandAlertResponse {
+	return r.sorted(func(a, b DomainItem) bool {
+		return a.DomainName < b.DomainName
+	})
+}
+
+// Reverse returns a copy of the response with the domains in reverse order.
+func (r *BrandAlertResponse) Reverse() *BrandAlertResponse {
+	res := &BrandAlertResponse{
+		DomainsList:  make([]DomainItem, len(r.DomainsList)),
+		DomainsCount: r.DomainsCount,
+	}
+
+	for i, item := range r.DomainsList {
+		res.DomainsList[len(r.DomainsList)-1-i] = item
+	}
+
+	return res
+}
+
+// groupBy groups the domains by the key.
+func (r *BrandAlertResponse) groupBy(key func(DomainItem) string) map[string][]DomainItem {
+	groups := make(map[string][]DomainItem)
+
+	for _, item := range r.DomainsList {
+		k := key(item)
+		groups[k] = append(groups[k], item)
+	}
+
+	return groups
+}
+
+// GroupByAction groups the domains by action.
+func (r *BrandAlertResponse) GroupByAction() map[Action][]DomainItem {
+	groups := make(map[Action][]DomainItem)
+
+	for _, item := range r.DomainsList {
+		groups[item.Action] = append(groups[item.Action], item)
+	}
+
+	return groups
+}
+
+// GroupByDate groups the domains by date formatted as "2006-01-02".
+func (r *BrandAlertResponse) GroupByDate() map[string][]DomainItem {
+	return r.groupBy(func(item DomainItem) string {
+		return formatDate(item.Date)
+	})
+}
+
+// GroupByTLD groups the domains by TLD.
+func (r *BrandAlertResponse) GroupByTLD() map[string][]DomainItem {
+	return r.groupBy(DomainItem.TLD)
+}
+
+// Summary is the summary of the domains.
+type Summary struct {
+	// Total is the number of domains.
+	Total int `json:"total"`
+
+	// ByAction is the number of domains per action.
+	ByAction map[Action]int `json:"byAction"`
+
+	// ByTLD is the number of domains per TLD.
+	ByTLD map[string]int `json:"byTLD"`
+
+	// ByDate is the number of domains per date formatted as "2006-01-02".
+	ByDate map[string]int `json:"byDate"`
+
+	// FirstDate is the date of the oldest domain.
+	FirstDate Time `json:"firstDate"`
+
+	// LastDate is the date of the newest domain.
+	LastDate Time `json:"lastDate"`
+}
+
+// Summary returns the summary counts of the domains.
+func (r *BrandAlertResponse) Summary() Summary {
+	summary := Summary{
+		Total:    len(r.DomainsList),
+		ByAction: make(map[Action]int),
+		ByTLD:    make(map[string]int),
+		ByDate:   make(map[string]int),
+	}
+
+	for _, item := range r.DomainsList {
+		summary.ByAction[item.Action]++
+		summary.ByTLD[item.TLD()]++
+		summary.ByDate[formatDate(item.Date)]++
+
+		if item.Date.IsZero() {
+			continue
+		}
+		if summary.FirstDate.IsZero() || item.Date.Before(summary.FirstDate) {
+			summary.FirstDate = item.Date
+		}
+		if item.Date.After(summary.LastDate) {
+			summary.LastDate = item.Date
+		}
+	}
+
+	return summary
+}
+
+// toSet converts the list to a set of normalized values.
+func toSet(list []string, normalize func(string) string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, v := range list {
+		set[normalize(v)] = true
+	}
+
+	return set
+}
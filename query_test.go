@@ -0,0 +1,142 @@
+package brandalert
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// newTestResponse returns the response for query tests.
+func newTestResponse(t *testing.T) *BrandAlertResponse {
+	resp, err := parse([]byte(`{"domainsCount":5,"domainsList":[
+{"domainName":"whoisdodster.com","date":"2022-10-30","action":"added"},
+{"domainName":"batchwhois.net","date":"2022-10-28","action":"dropped"},
+{"domainName":"betterwhoislookup.com","date":"2022-10-29","action":"discovered"},
+{"domainName":"whoisdomainlookup.info","date":"2022-10-30","action":"updated"},
+{"domainName":"awhois.com","date":"2022-10-30","action":"added"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &resp.BrandAlertResponse
+}
+
+// TestFilter tests the Filter functions.
+func TestFilter(t *testing.T) {
+	resp := newTestResponse(t)
+
+	tests := []struct {
+		name string
+		got  *BrandAlertResponse
+		want []string
+	}{
+		{
+			name: "action",
+			got:  resp.FilterAction(Added, Dropped),
+			want: []string{"whoisdodster.com", "batchwhois.net", "awhois.com"},
+		},
+		{
+			name: "date range",
+			got:  resp.FilterDateRange(time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC)),
+			want: []string{"betterwhoislookup.com"},
+		},
+		{
+			name: "open date range",
+			got:  resp.FilterDateRange(time.Time{}, time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC)),
+			want: []string{"batchwhois.net", "betterwhoislookup.com"},
+		},
+		{
+			name: "tld",
+			got:  resp.FilterTLD(".NET", "info"),
+			want: []string{"batchwhois.net", "whoisdomainlookup.info"},
+		},
+		{
+			name: "regexp",
+			got:  resp.FilterRegexp(regexp.MustCompile(`^whois`)),
+			want: []string{"whoisdodster.com", "whoisdomainlookup.info"},
+		},
+		{
+			name: "chained",
+			got:  resp.FilterAction(Added).FilterTLD("com").SortByName(),
+			want: []string{"awhois.com", "whoisdodster.com"},
+		},
+		{
+			name: "sort by date",
+			got:  resp.SortByDate(),
+			want: []string{"batchwhois.net", "betterwhoislookup.com", "awhois.com", "whoisdodster.com", "whoisdomainlookup.info"},
+		},
+		{
+			name: "reverse",
+			got:  resp.SortByName().Reverse(),
+			want: []string{"whoisdomainlookup.info", "whoisdodster.com", "betterwhoislookup.com", "batchwhois.net", "awhois.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domainNames(tt.got.DomainsList); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if resp.DomainsList[0].DomainName != "whoisdodster.com" {
+		t.Errorf("the original response is modified: %v", domainNames(resp.DomainsList))
+	}
+}
+
+// TestGroupBy tests the GroupBy functions.
+func TestGroupBy(t *testing.T) {
+	resp := newTestResponse(t)
+
+	byAction := make(map[Action][]string)
+	for k, v := range resp.GroupByAction() {
+		byAction[k] = domainNames(v)
+	}
+
+	wantByAction := map[Action][]string{
+		Added:      {"whoisdodster.com", "awhois.com"},
+		Dropped:    {"batchwhois.net"},
+		Discovered: {"betterwhoislookup.com"},
+		Updated:    {"whoisdomainlookup.info"},
+	}
+	if !reflect.DeepEqual(byAction, wantByAction) {
+		t.Errorf("GroupByAction() got = %v, want %v", byAction, wantByAction)
+	}
+
+	byDate := make(map[string][]string)
+	for k, v := range resp.GroupByDate() {
+		byDate[k] = domainNames(v)
+	}
+
+	wantByDate := map[string][]string{
+		"2022-10-28": {"batchwhois.net"},
+		"2022-10-29": {"betterwhoislookup.com"},
+		"2022-10-30": {"whoisdodster.com", "whoisdomainlookup.info", "awhois.com"},
+	}
+	if !reflect.DeepEqual(byDate, wantByDate) {
+		t.Errorf("GroupByDate() got = %v, want %v", byDate, wantByDate)
+	}
+
+	if got := len(resp.GroupByTLD()["com"]); got != 3 {
+		t.Errorf("GroupByTLD() got = %v, want %v", got, 3)
+	}
+}
+
+// TestSummary tests the Summary function.
+func TestSummary(t *testing.T) {
+	got := newTestResponse(t).Summary()
+
+	want := Summary{
+		Total:     5,
+		ByAction:  map[Action]int{Added: 2, Dropped: 1, Discovered: 1, Updated: 1},
+		ByTLD:     map[string]int{"com": 3, "net": 1, "info": 1},
+		ByDate:    map[string]int{"2022-10-28": 1, "2022-10-29": 1, "2022-10-30": 3},
+		FirstDate: Time(time.Date(2022, 10, 28, 0, 0, 0, 0, time.UTC)),
+		LastDate:  Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)),
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary() got = %+v, want %+v", got, want)
+	}
+}
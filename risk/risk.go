@@ -0,0 +1,427 @@
+// Package risk scores the domains returned by Brand Alert API against a
+// brand profile. Every score is the sum of explainable components, so
+// analysts can sort, threshold and review why a domain was ranked high.
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/internal/punycode"
+	"github.com/whois-api-llc/brand-alert-go/publicsuffix"
+)
+
+// MaxScore is the highest possible score.
+const MaxScore = 100
+
+// Names of the score components.
+const (
+	ComponentOfficial   = "official"
+	ComponentTrademark  = "trademark"
+	ComponentSimilarity = "similarity"
+	ComponentHomoglyph  = "homoglyph"
+	ComponentKeyword    = "keyword"
+	ComponentHyphen     = "hyphen"
+	ComponentTLD        = "tld"
+	ComponentAction     = "action"
+	ComponentRegistrar  = "registrar"
+)
+
+// Profile describes the brand the domains are scored against.
+type Profile struct {
+	// Name is the brand name.
+	Name string `json:"name"`
+
+	// OfficialDomains is the list of domains owned by the brand. These domains
+	// and their subdomains are never considered risky.
+	OfficialDomains []string `json:"officialDomains,omitempty"`
+
+	// Trademarks is the list of protected marks, e.g. "paypal".
+	// If empty, the brand name is used.
+	Trademarks []string `json:"trademarks,omitempty"`
+
+	// AllowedRegistrars is the list of registrars the brand registers its
+	// domains with. Domains registered through them get a lower score.
+	AllowedRegistrars []string `json:"allowedRegistrars,omitempty"`
+
+	// AllowedTLDs is the list of TLDs the brand legitimately uses.
+	// The TLD reputation is not taken into account for them.
+	AllowedTLDs []string `json:"allowedTLDs,omitempty"`
+}
+
+// Weights is the set of points added by every score component.
+type Weights struct {
+	// TrademarkExact is added when a label equals a trademark.
+	TrademarkExact float64 `json:"trademarkExact"`
+
+	// TrademarkContained is added when the domain contains a trademark.
+	TrademarkContained float64 `json:"trademarkContained"`
+
+	// EditDistance maps edit distance to a trademark to points. Distance 0 is
+	// covered by TrademarkContained.
+	EditDistance map[int]float64 `json:"editDistance"`
+
+	// Homoglyph is added when the domain imitates a trademark with
+	// lookalike characters.
+	Homoglyph float64 `json:"homoglyph"`
+
+	// Keyword is added for every suspicious keyword found in the domain.
+	Keyword float64 `json:"keyword"`
+
+	// KeywordMax is the limit of points added by keywords.
+	KeywordMax float64 `json:"keywordMax"`
+
+	// Hyphen is added for every hyphen in the domain.
+	Hyphen float64 `json:"hyphen"`
+
+	// HyphenMax is the limit of points added by hyphens.
+	HyphenMax float64 `json:"hyphenMax"`
+
+	// Actions maps the domain action to points.
+	Actions map[brandalert.Action]float64 `json:"actions"`
+
+	// AllowedRegistrar is added when the domain is registered through one
+	// of the allowed registrars. It is usually negative.
+	AllowedRegistrar float64 `json:"allowedRegistrar"`
+}
+
+// DefaultWeights returns the recommended weights.
+func DefaultWeights() Weights {
+	return Weights{
+		TrademarkExact:     40,
+		TrademarkContained: 30,
+		EditDistance: map[int]float64{
+			1: 30,
+			2: 15,
+		},
+		Homoglyph:  25,
+		Keyword:    10,
+		KeywordMax: 20,
+		Hyphen:     5,
+		HyphenMax:  10,
+		Actions: map[brandalert.Action]float64{
+			brandalert.Added:      10,
+			brandalert.Discovered: 10,
+			brandalert.Updated:    5,
+			brandalert.Dropped:    -20,
+		},
+		AllowedRegistrar: -30,
+	}
+}
+
+// DefaultKeywords returns the keywords often combined with a brand name in
+// phishing domains.
+func DefaultKeywords() []string {
+	return []string{
+		"login", "signin", "logon", "secure", "security", "support", "account",
+		"verify", "verification", "update", "billing", "payment", "password",
+		"wallet", "helpdesk", "service", "auth", "recovery", "unlock", "confirm",
+	}
+}
+
+// DefaultTLDReputation returns the points added for TLDs frequently
+// used in abuse.
+func DefaultTLDReputation() map[string]float64 {
+	return map[string]float64{
+		"tk": 15, "ml": 15, "ga": 15, "cf": 15, "gq": 15,
+		"xyz": 10, "top": 10, "icu": 10, "buzz": 10, "cyou": 10, "rest": 10,
+		"online": 8, "site": 8, "live": 8, "support": 8, "help": 8, "click": 8,
+		"info": 5, "club": 5, "shop": 5, "work": 5, "store": 5, "app": 3,
+	}
+}
+
+// Component is a part of the score with its explanation.
+type Component struct {
+	// Name is the component name, e.g. "keyword".
+	Name string `json:"name"`
+
+	// Points is the number of points added by the component.
+	Points float64 `json:"points"`
+
+	// Reason explains why the points were added.
+	Reason string `json:"reason"`
+}
+
+// Score is the risk score of the domain.
+type Score struct {
+	// Item is the scored domain.
+	Item brandalert.DomainItem `json:"item"`
+
+	// Total is the sum of the component points between 0 and MaxScore.
+	Total float64 `json:"total"`
+
+	// Components is the list of score components.
+	Components []Component `json:"components"`
+}
+
+// String returns the score with its components as a string.
+func (s Score) String() string {
+	parts := make([]string, 0, len(s.Components))
+	for _, c := range s.Components {
+		parts = append(parts, fmt.Sprintf("%s %+g (%s)", c.Name, c.Points, c.Reason))
+	}
+
+	return fmt.Sprintf("%s %g: %s", s.Item.DomainName, s.Total, strings.Join(parts, ", "))
+}
+
+// Scores is the list of scores.
+type Scores []Score
+
+// Sort sorts scores by Total in descending order, then by domain name.
+func (s Scores) Sort() {
+	sort.SliceStable(s, func(i, j int) bool {
+		if s[i].Total != s[j].Total {
+			return s[i].Total > s[j].Total
+		}
+		return s[i].Item.DomainName < s[j].Item.DomainName
+	})
+}
+
+// Above returns the scores with Total greater than or equal to threshold.
+func (s Scores) Above(threshold float64) Scores {
+	var res Scores
+
+	for _, score := range s {
+		if score.Total >= threshold {
+			res = append(res, score)
+		}
+	}
+
+	return res
+}
+
+// Scorer computes risk scores of domains.
+type Scorer struct {
+	// Profile is the brand profile.
+	Profile Profile
+
+	// Weights is the set of points added by every component.
+	Weights Weights
+
+	// Keywords is the list of suspicious keywords.
+	Keywords []string
+
+	// TLDReputation maps a TLD to the points added for it.
+	TLDReputation map[string]float64
+
+	// Registrar returns the registrar of the domain. It is optional, as Brand
+	// Alert API doesn't return registrars. Return an empty string if unknown.
+	Registrar func(domainName string) string
+}
+
+// NewScorer creates Scorer with the default weights, keywords and TLD reputation table.
+func NewScorer(profile Profile) *Scorer {
+	return &Scorer{
+		Profile:       profile,
+		Weights:       DefaultWeights(),
+		Keywords:      DefaultKeywords(),
+		TLDReputation: DefaultTLDReputation(),
+	}
+}
+
+// ScoreAll scores all items and returns them sorted by Total in descending order.
+func (s *Scorer) ScoreAll(items []brandalert.DomainItem) Scores {
+	scores := make(Scores, 0, len(items))
+
+	for _, item := range items {
+		scores = append(scores, s.Score(item))
+	}
+
+	scores.Sort()
+
+	return scores
+}
+
+// Score computes the risk score of the domain.
+func (s *Scorer) Score(item brandalert.DomainItem) Score {
+	score := Score{Item: item}
+
+	domain := strings.TrimSuffix(strings.ToLower(item.DomainName), ".")
+
+	if official, ok := s.official(domain); ok {
+		score.Components = []Component{{
+			Name:   ComponentOfficial,
+			Reason: "official domain " + official,
+		}}
+		return score
+	}
+
+	tld, name := splitTLD(domain)
+	if unicodeName, err := punycode.ToUnicode(name); err == nil {
+		name = unicodeName
+	}
+	compact := strings.NewReplacer("-", "", ".", "").Replace(name)
+
+	add := func(name string, points float64, reason string) {
+		if points != 0 {
+			score.Components = append(score.Components, Component{name, points, reason})
+		}
+	}
+
+	similar := false
+
+	for _, tm := range s.trademarks() {
+		if points, m := s.similarity(name, compact, tm); points != 0 {
+			add(m.component, points, m.text)
+			similar = true
+			break
+		}
+	}
+
+	if skel := skeleton(compact); skel != compact {
+		for _, tm := range s.trademarks() {
+			if strings.Contains(skel, tm) && !strings.Contains(compact, tm) {
+				add(ComponentHomoglyph, s.Weights.Homoglyph, "imitates "+quote(tm)+" with lookalike characters")
+				similar = true
+				break
+			}
+		}
+	}
+
+	var found []string
+	for _, kw := range s.Keywords {
+		if strings.Contains(compact, kw) {
+			found = append(found, kw)
+		}
+	}
+	if len(found) > 0 {
+		points := capPoints(s.Weights.Keyword*float64(len(found)), s.Weights.KeywordMax)
+		reason := "contains " + strings.Join(found, ", ")
+		if similar {
+			reason += " combined with the brand"
+		}
+		add(ComponentKeyword, points, reason)
+	}
+
+	if hyphens := strings.Count(name, "-"); hyphens > 0 {
+		add(ComponentHyphen, capPoints(s.Weights.Hyphen*float64(hyphens), s.Weights.HyphenMax),
+			fmt.Sprintf("%d hyphen(s)", hyphens))
+	}
+
+	if !contains(s.Profile.AllowedTLDs, tld) {
+		add(ComponentTLD, s.TLDReputation[tld], "TLD ."+tld+" is frequently abused")
+	}
+
+	add(ComponentAction, s.Weights.Actions[item.Action], "domain is "+string(item.Action))
+
+	if s.Registrar != nil {
+		if registrar := s.Registrar(item.DomainName); registrar != "" && contains(s.Profile.AllowedRegistrars, registrar) {
+			add(ComponentRegistrar, s.Weights.AllowedRegistrar, "registered through allowed registrar "+registrar)
+		}
+	}
+
+	for _, c := range score.Components {
+		score.Total += c.Points
+	}
+
+	switch {
+	case score.Total < 0:
+		score.Total = 0
+	case score.Total > MaxScore:
+		score.Total = MaxScore
+	}
+
+	return score
+}
+
+// match is the explanation of the similarity component.
+type match struct {
+	component string
+	text      string
+}
+
+// similarity returns the points for the similarity of the domain name to the trademark.
+func (s *Scorer) similarity(name, compact, tm string) (float64, match) {
+	for _, label := range strings.FieldsFunc(name, func(r rune) bool { return r == '.' || r == '-' }) {
+		if label == tm {
+			return s.Weights.TrademarkExact, match{ComponentTrademark, "label equals " + quote(tm)}
+		}
+	}
+
+	if strings.Contains(compact, tm) {
+		return s.Weights.TrademarkContained, match{ComponentTrademark, "contains " + quote(tm)}
+	}
+
+	d := minDistance(compact, tm)
+	if points := s.Weights.EditDistance[d]; points != 0 {
+		return points, match{ComponentSimilarity, fmt.Sprintf("edit distance %d to %s", d, quote(tm))}
+	}
+
+	return 0, match{}
+}
+
+// official returns the official domain matching the domain.
+func (s *Scorer) official(domain string) (string, bool) {
+	for _, official := range s.Profile.OfficialDomains {
+		official = strings.TrimSuffix(strings.ToLower(official), ".")
+		if domain == official || strings.HasSuffix(domain, "."+official) {
+			return official, true
+		}
+	}
+
+	return "", false
+}
+
+// trademarks returns the normalized trademarks.
+func (s *Scorer) trademarks() []string {
+	marks := s.Profile.Trademarks
+	if len(marks) == 0 && s.Profile.Name != "" {
+		marks = []string{s.Profile.Name}
+	}
+
+	res := make([]string, 0, len(marks))
+	for _, tm := range marks {
+		tm = strings.ToLower(strings.Join(strings.Fields(tm), ""))
+		if tm != "" {
+			res = append(res, tm)
+		}
+	}
+
+	return res
+}
+
+// splitTLD splits the domain into the TLD and the labels left of the public suffix.
+func splitTLD(domain string) (tld, name string) {
+	suffix := publicsuffix.PublicSuffix(domain)
+	if !strings.HasSuffix(domain, suffix) {
+		suffix = domain[strings.LastIndexByte(domain, '.')+1:]
+	}
+
+	tld = suffix
+	if i := strings.LastIndexByte(suffix, '.'); i >= 0 {
+		tld = suffix[i+1:]
+	}
+
+	name = strings.TrimSuffix(strings.TrimSuffix(domain, suffix), ".")
+	if name == "" {
+		name = domain
+	}
+
+	return tld, name
+}
+
+// contains reports whether the list contains the value ignoring case.
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimPrefix(item, "."), v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// capPoints limits points by max if max is not zero.
+func capPoints(points, max float64) float64 {
+	if max != 0 && points > max {
+		return max
+	}
+	return points
+}
+
+// quote returns the quoted string.
+func quote(s string) string {
+	return `"` + s + `"`
+}
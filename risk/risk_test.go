@@ -0,0 +1,162 @@
+package risk
+
+import (
+	"testing"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// TestScore tests the Score function.
+func TestScore(t *testing.T) {
+	scorer := NewScorer(Profile{
+		Name:              "PayPal",
+		OfficialDomains:   []string{"paypal.com"},
+		AllowedRegistrars: []string{"MarkMonitor Inc."},
+		AllowedTLDs:       []string{"com", "me"},
+	})
+	scorer.Registrar = func(domainName string) string {
+		if domainName == "paypal-secure.net" {
+			return "MarkMonitor Inc."
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name       string
+		action     brandalert.Action
+		want       float64
+		components []string
+	}{
+		{
+			name:       "www.paypal.com",
+			action:     brandalert.Added,
+			want:       0,
+			components: []string{ComponentOfficial},
+		},
+		{
+			name:       "paypa1-login.tk",
+			action:     brandalert.Added,
+			want:       95,
+			components: []string{ComponentSimilarity, ComponentHomoglyph, ComponentKeyword, ComponentHyphen, ComponentTLD, ComponentAction},
+		},
+		{
+			name:       "paypal.xyz",
+			action:     brandalert.Discovered,
+			want:       60,
+			components: []string{ComponentTrademark, ComponentTLD, ComponentAction},
+		},
+		{
+			name:       "mypaypalshop.com",
+			action:     brandalert.Dropped,
+			want:       10,
+			components: []string{ComponentTrademark, ComponentAction},
+		},
+		{
+			name:       "xn--pypal-4ve.com",
+			action:     brandalert.Updated,
+			want:       60,
+			components: []string{ComponentSimilarity, ComponentHomoglyph, ComponentAction},
+		},
+		{
+			name:       "paypal-secure.net",
+			action:     brandalert.Added,
+			want:       35,
+			components: []string{ComponentTrademark, ComponentKeyword, ComponentHyphen, ComponentAction, ComponentRegistrar},
+		},
+		{
+			name:       "gardening.com",
+			action:     brandalert.Dropped,
+			want:       0,
+			components: []string{ComponentAction},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scorer.Score(brandalert.DomainItem{DomainName: tt.name, Action: tt.action})
+
+			if got.Total != tt.want {
+				t.Errorf("Score() got = %v, want %v", got, tt.want)
+			}
+
+			if len(got.Components) != len(tt.components) {
+				t.Fatalf("Score() got = %v, want components %v", got, tt.components)
+			}
+
+			for i, c := range got.Components {
+				if c.Name != tt.components[i] || c.Reason == "" {
+					t.Errorf("Score() got component = %v, want %v", c, tt.components[i])
+				}
+			}
+		})
+	}
+}
+
+// TestScoreAll tests the ScoreAll function.
+func TestScoreAll(t *testing.T) {
+	scorer := NewScorer(Profile{Trademarks: []string{"whois"}})
+
+	scores := scorer.ScoreAll([]brandalert.DomainItem{
+		{DomainName: "example.com", Action: brandalert.Added},
+		{DomainName: "whois-login.tk", Action: brandalert.Added},
+		{DomainName: "whois.com", Action: brandalert.Dropped},
+		{DomainName: "wh0is.com", Action: brandalert.Added},
+	})
+
+	want := []string{"whois-login.tk", "wh0is.com", "whois.com", "example.com"}
+	for i, score := range scores {
+		if score.Item.DomainName != want[i] {
+			t.Errorf("ScoreAll() got = %v, want %v", score.Item.DomainName, want[i])
+		}
+	}
+
+	if above := scores.Above(50); len(above) != 2 {
+		t.Errorf("Above() got = %v, want 2 items", above)
+	}
+}
+
+// TestLevenshtein tests the levenshtein and minDistance functions.
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+		min  int
+	}{
+		{"paypal", "paypal", 0, 0},
+		{"paypa1", "paypal", 1, 1},
+		{"pypal", "paypal", 1, 1},
+		{"paypal-login", "paypal", 6, 0},
+		{"loginpaypa1", "paypal", 6, 1},
+		{"", "paypal", 6, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a, func(t *testing.T) {
+			if got := levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("levenshtein() got = %v, want %v", got, tt.want)
+			}
+
+			if got := minDistance(tt.a, tt.b); got != tt.min {
+				t.Errorf("minDistance() got = %v, want %v", got, tt.min)
+			}
+		})
+	}
+}
+
+// TestSplitTLD tests the splitTLD function.
+func TestSplitTLD(t *testing.T) {
+	tests := []struct {
+		domain, tld, name string
+	}{
+		{"paypal.com", "com", "paypal"},
+		{"login.paypal.co.uk", "uk", "login.paypal"},
+		{"paypal.中国", "中国", "paypal"},
+		{"localhost", "localhost", "localhost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			tld, name := splitTLD(tt.domain)
+			if tld != tt.tld || name != tt.name {
+				t.Errorf("splitTLD() got = %v, %v, want %v, %v", tld, name, tt.tld, tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package risk
+
+import (
+	"strings"
+)
+
+// confusables maps the characters commonly used in lookalike domains to
+// the ASCII characters they imitate.
+var confusables = map[rune]rune{
+	// Digits.
+	'0': 'o', '1': 'l', '3': 'e', '5': 's',
+	// Cyrillic.
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'һ': 'h', 'ӏ': 'l', 'ԛ': 'q',
+	'ԝ': 'w', 'к': 'k', 'м': 'm', 'н': 'h', 'т': 't', 'в': 'b',
+	// Greek.
+	'α': 'a', 'ο': 'o', 'ν': 'v', 'ρ': 'p', 'ι': 'i', 'κ': 'k', 'τ': 't',
+	'υ': 'u', 'ε': 'e',
+	// Armenian.
+	'ո': 'n', 'ս': 'u', 'օ': 'o', 'հ': 'h',
+	// Latin with diacritics.
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ı': 'i',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n', 'ý': 'y', 'ÿ': 'y', 'ß': 'b', 'ɡ': 'g',
+}
+
+// confusableSequences are the multi-character sequences imitating a single letter.
+var confusableSequences = strings.NewReplacer("rn", "m", "vv", "w", "cl", "d")
+
+// skeleton returns the ASCII form of the string with all confusable
+// characters replaced by the characters they imitate.
+func skeleton(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+
+	return confusableSequences.Replace(b.String())
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// minDistance returns the smallest edit distance between the term and any
+// substring of s having roughly the same length.
+func minDistance(s, term string) int {
+	rs, n := []rune(s), len([]rune(term))
+
+	best := levenshtein(s, term)
+
+	for length := n - 1; length <= n+1; length++ {
+		if length <= 0 || length > len(rs) {
+			continue
+		}
+
+		for i := 0; i+length <= len(rs); i++ {
+			if d := levenshtein(string(rs[i:i+length]), term); d < best {
+				best = d
+			}
+		}
+	}
+
+	return best
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,514 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.12
+// source: rpc/brandalertpb/brand_alert.proto
+
+package brandalertpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Action is the domain activity.
+type Action int32
+
+const (
+	Action_ACTION_UNSPECIFIED Action = 0
+	Action_ACTION_ADDED       Action = 1
+	Action_ACTION_UPDATED     Action = 2
+	Action_ACTION_DROPPED     Action = 3
+	Action_ACTION_DISCOVERED  Action = 4
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_ADDED",
+		2: "ACTION_UPDATED",
+		3: "ACTION_DROPPED",
+		4: "ACTION_DISCOVERED",
+	}
+	Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_ADDED":       1,
+		"ACTION_UPDATED":     2,
+		"ACTION_DROPPED":     3,
+		"ACTION_DISCOVERED":  4,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_brandalertpb_brand_alert_proto_enumTypes[0].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_rpc_brandalertpb_brand_alert_proto_enumTypes[0]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_brandalertpb_brand_alert_proto_rawDescGZIP(), []int{0}
+}
+
+// BrandAlertRequest is the search request. The API key is added by the server.
+type BrandAlertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Terms present in the domain names, 1 to 4 items.
+	IncludeSearchTerms []string `protobuf:"bytes,1,rep,name=include_search_terms,json=includeSearchTerms,proto3" json:"include_search_terms,omitempty"`
+	// Terms absent in the domain names, up to 4 items.
+	ExcludeSearchTerms []string `protobuf:"bytes,2,rep,name=exclude_search_terms,json=excludeSearchTerms,proto3" json:"exclude_search_terms,omitempty"`
+	// Search activities discovered since the date in the YYYY-MM-DD format.
+	SinceDate string `protobuf:"bytes,3,opt,name=since_date,json=sinceDate,proto3" json:"since_date,omitempty"`
+	// Enrich the search terms with their possible typos.
+	WithTypos bool `protobuf:"varint,4,opt,name=with_typos,json=withTypos,proto3" json:"with_typos,omitempty"`
+	// Encode the domain names to Punycode. Default: true.
+	Punycode *bool `protobuf:"varint,5,opt,name=punycode,proto3,oneof" json:"punycode,omitempty"`
+}
+
+func (x *BrandAlertRequest) Reset() {
+	*x = BrandAlertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BrandAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrandAlertRequest) ProtoMessage() {}
+
+func (x *BrandAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrandAlertRequest.ProtoReflect.Descriptor instead.
+func (*BrandAlertRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_brandalertpb_brand_alert_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BrandAlertRequest) GetIncludeSearchTerms() []string {
+	if x != nil {
+		return x.IncludeSearchTerms
+	}
+	return nil
+}
+
+func (x *BrandAlertRequest) GetExcludeSearchTerms() []string {
+	if x != nil {
+		return x.ExcludeSearchTerms
+	}
+	return nil
+}
+
+func (x *BrandAlertRequest) GetSinceDate() string {
+	if x != nil {
+		return x.SinceDate
+	}
+	return ""
+}
+
+func (x *BrandAlertRequest) GetWithTypos() bool {
+	if x != nil {
+		return x.WithTypos
+	}
+	return false
+}
+
+func (x *BrandAlertRequest) GetPunycode() bool {
+	if x != nil && x.Punycode != nil {
+		return *x.Punycode
+	}
+	return false
+}
+
+// PreviewResponse is the number of matching domains.
+type PreviewResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainsCount int32 `protobuf:"varint,1,opt,name=domains_count,json=domainsCount,proto3" json:"domains_count,omitempty"`
+}
+
+func (x *PreviewResponse) Reset() {
+	*x = PreviewResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PreviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewResponse) ProtoMessage() {}
+
+func (x *PreviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewResponse.ProtoReflect.Descriptor instead.
+func (*PreviewResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_brandalertpb_brand_alert_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PreviewResponse) GetDomainsCount() int32 {
+	if x != nil {
+		return x.DomainsCount
+	}
+	return 0
+}
+
+// DomainItem is the domain activity.
+type DomainItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	Action     Action `protobuf:"varint,2,opt,name=action,proto3,enum=brandalert.v1.Action" json:"action,omitempty"`
+	// The activity date in the YYYY-MM-DD format.
+	Date string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+}
+
+func (x *DomainItem) Reset() {
+	*x = DomainItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DomainItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainItem) ProtoMessage() {}
+
+func (x *DomainItem) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainItem.ProtoReflect.Descriptor instead.
+func (*DomainItem) Descriptor() ([]byte, []int) {
+	return file_rpc_brandalertpb_brand_alert_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DomainItem) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *DomainItem) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_ACTION_UNSPECIFIED
+}
+
+func (x *DomainItem) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+// BrandAlertResponse is the list of matching domains.
+type BrandAlertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainsList  []*DomainItem `protobuf:"bytes,1,rep,name=domains_list,json=domainsList,proto3" json:"domains_list,omitempty"`
+	DomainsCount int32         `protobuf:"varint,2,opt,name=domains_count,json=domainsCount,proto3" json:"domains_count,omitempty"`
+}
+
+func (x *BrandAlertResponse) Reset() {
+	*x = BrandAlertResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BrandAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrandAlertResponse) ProtoMessage() {}
+
+func (x *BrandAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_brandalertpb_brand_alert_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrandAlertResponse.ProtoReflect.Descriptor instead.
+func (*BrandAlertResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_brandalertpb_brand_alert_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BrandAlertResponse) GetDomainsList() []*DomainItem {
+	if x != nil {
+		return x.DomainsList
+	}
+	return nil
+}
+
+func (x *BrandAlertResponse) GetDomainsCount() int32 {
+	if x != nil {
+		return x.DomainsCount
+	}
+	return 0
+}
+
+var File_rpc_brandalertpb_brand_alert_proto protoreflect.FileDescriptor
+
+var file_rpc_brandalertpb_brand_alert_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x72, 0x70, 0x63, 0x2f, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74,
+	0x70, 0x62, 0x2f, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x5f, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74,
+	0x2e, 0x76, 0x31, 0x22, 0xe3, 0x01, 0x0a, 0x11, 0x42, 0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x74, 0x65, 0x72, 0x6d,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x54, 0x65, 0x72, 0x6d, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x74, 0x65,
+	0x72, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x65, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x54, 0x65, 0x72, 0x6d, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x77, 0x69, 0x74, 0x68, 0x5f, 0x74, 0x79, 0x70, 0x6f, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x77, 0x69, 0x74, 0x68, 0x54, 0x79, 0x70, 0x6f, 0x73, 0x12, 0x1f, 0x0a, 0x08, 0x70,
+	0x75, 0x6e, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52,
+	0x08, 0x70, 0x75, 0x6e, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x70, 0x75, 0x6e, 0x79, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x36, 0x0a, 0x0f, 0x50, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x70, 0x0a, 0x0a, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x12,
+	0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x2d, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x15, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x65, 0x22, 0x77, 0x0a, 0x12, 0x42, 0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65, 0x72,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x73, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x0b, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x2a, 0x71, 0x0a, 0x06,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10,
+	0x0a, 0x0c, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x12, 0x0a, 0x0e, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54,
+	0x45, 0x44, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44,
+	0x52, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x43, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x45, 0x44, 0x10, 0x04, 0x32,
+	0x82, 0x02, 0x0a, 0x11, 0x42, 0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x07, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x12, 0x20, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4f, 0x0a, 0x08, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x12, 0x20,
+	0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x21, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x12, 0x20, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65,
+	0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x61, 0x6e, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61,
+	0x6c, 0x65, 0x72, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x49, 0x74,
+	0x65, 0x6d, 0x30, 0x01, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x77, 0x68, 0x6f, 0x69, 0x73, 0x2d, 0x61, 0x70, 0x69, 0x2d, 0x6c, 0x6c, 0x63,
+	0x2f, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x2d, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x2d, 0x67, 0x6f, 0x2f,
+	0x72, 0x70, 0x63, 0x2f, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_brandalertpb_brand_alert_proto_rawDescOnce sync.Once
+	file_rpc_brandalertpb_brand_alert_proto_rawDescData = file_rpc_brandalertpb_brand_alert_proto_rawDesc
+)
+
+func file_rpc_brandalertpb_brand_alert_proto_rawDescGZIP() []byte {
+	file_rpc_brandalertpb_brand_alert_proto_rawDescOnce.Do(func() {
+		file_rpc_brandalertpb_brand_alert_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_brandalertpb_brand_alert_proto_rawDescData)
+	})
+	return file_rpc_brandalertpb_brand_alert_proto_rawDescData
+}
+
+var file_rpc_brandalertpb_brand_alert_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_rpc_brandalertpb_brand_alert_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rpc_brandalertpb_brand_alert_proto_goTypes = []interface{}{
+	(Action)(0),                // 0: brandalert.v1.Action
+	(*BrandAlertRequest)(nil),  // 1: brandalert.v1.BrandAlertRequest
+	(*PreviewResponse)(nil),    // 2: brandalert.v1.PreviewResponse
+	(*DomainItem)(nil),         // 3: brandalert.v1.DomainItem
+	(*BrandAlertResponse)(nil), // 4: brandalert.v1.BrandAlertResponse
+}
+var file_rpc_brandalertpb_brand_alert_proto_depIdxs = []int32{
+	0, // 0: brandalert.v1.DomainItem.action:type_name -> brandalert.v1.Action
+	3, // 1: brandalert.v1.BrandAlertResponse.domains_list:type_name -> brandalert.v1.DomainItem
+	1, // 2: brandalert.v1.BrandAlertService.Preview:input_type -> brandalert.v1.BrandAlertRequest
+	1, // 3: brandalert.v1.BrandAlertService.Purchase:input_type -> brandalert.v1.BrandAlertRequest
+	1, // 4: brandalert.v1.BrandAlertService.StreamPurchase:input_type -> brandalert.v1.BrandAlertRequest
+	2, // 5: brandalert.v1.BrandAlertService.Preview:output_type -> brandalert.v1.PreviewResponse
+	4, // 6: brandalert.v1.BrandAlertService.Purchase:output_type -> brandalert.v1.BrandAlertResponse
+	3, // 7: brandalert.v1.BrandAlertService.StreamPurchase:output_type -> brandalert.v1.DomainItem
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_brandalertpb_brand_alert_proto_init() }
+func file_rpc_brandalertpb_brand_alert_proto_init() {
+	if File_rpc_brandalertpb_brand_alert_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_brandalertpb_brand_alert_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BrandAlertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_brandalertpb_brand_alert_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PreviewResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_brandalertpb_brand_alert_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DomainItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_brandalertpb_brand_alert_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BrandAlertResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_rpc_brandalertpb_brand_alert_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_brandalertpb_brand_alert_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_brandalertpb_brand_alert_proto_goTypes,
+		DependencyIndexes: file_rpc_brandalertpb_brand_alert_proto_depIdxs,
+		EnumInfos:         file_rpc_brandalertpb_brand_alert_proto_enumTypes,
+		MessageInfos:      file_rpc_brandalertpb_brand_alert_proto_msgTypes,
+	}.Build()
+	File_rpc_brandalertpb_brand_alert_proto = out.File
+	file_rpc_brandalertpb_brand_alert_proto_rawDesc = nil
+	file_rpc_brandalertpb_brand_alert_proto_goTypes = nil
+	file_rpc_brandalertpb_brand_alert_proto_depIdxs = nil
+}
@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: rpc/brandalertpb/brand_alert.proto
+
+package brandalertpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BrandAlertServiceClient is the client API for BrandAlertService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BrandAlertServiceClient interface {
+	// Preview returns only the number of domains. No credits deducted.
+	Preview(ctx context.Context, in *BrandAlertRequest, opts ...grpc.CallOption) (*PreviewResponse, error)
+	// Purchase returns the matching domains.
+	Purchase(ctx context.Context, in *BrandAlertRequest, opts ...grpc.CallOption) (*BrandAlertResponse, error)
+	// StreamPurchase returns the matching domains one by one.
+	StreamPurchase(ctx context.Context, in *BrandAlertRequest, opts ...grpc.CallOption) (BrandAlertService_StreamPurchaseClient, error)
+}
+
+type brandAlertServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBrandAlertServiceClient(cc grpc.ClientConnInterface) BrandAlertServiceClient {
+	return &brandAlertServiceClient{cc}
+}
+
+func (c *brandAlertServiceClient) Preview(ctx context.Context, in *BrandAlertRequest, opts ...grpc.CallOption) (*PreviewResponse, error) {
+	out := new(PreviewResponse)
+	err := c.cc.Invoke(ctx, "/brandalert.v1.BrandAlertService/Preview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brandAlertServiceClient) Purchase(ctx context.Context, in *BrandAlertRequest, opts ...grpc.CallOption) (*BrandAlertResponse, error) {
+	out := new(BrandAlertResponse)
+	err := c.cc.Invoke(ctx, "/brandalert.v1.BrandAlertService/Purchase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brandAlertServiceClient) StreamPurchase(ctx context.Context, in *BrandAlertRequest, opts ...grpc.CallOption) (BrandAlertService_StreamPurchaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BrandAlertService_ServiceDesc.Streams[0], "/brandalert.v1.BrandAlertService/StreamPurchase", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &brandAlertServiceStreamPurchaseClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BrandAlertService_StreamPurchaseClient interface {
+	Recv() (*DomainItem, error)
+	grpc.ClientStream
+}
+
+type brandAlertServiceStreamPurchaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *brandAlertServiceStreamPurchaseClient) Recv() (*DomainItem, error) {
+	m := new(DomainItem)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BrandAlertServiceServer is the server API for BrandAlertService service.
+// All implementations must embed UnimplementedBrandAlertServiceServer
+// for forward compatibility
+type BrandAlertServiceServer interface {
+	// Preview returns only the number of domains. No credits deducted.
+	Preview(context.Context, *BrandAlertRequest) (*PreviewResponse, error)
+	// Purchase returns the matching domains.
+	Purchase(context.Context, *BrandAlertRequest) (*BrandAlertResponse, error)
+	// StreamPurchase returns the matching domains one by one.
+	StreamPurchase(*BrandAlertRequest, BrandAlertService_StreamPurchaseServer) error
+	mustEmbedUnimplementedBrandAlertServiceServer()
+}
+
+// UnimplementedBrandAlertServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBrandAlertServiceServer struct {
+}
+
+func (UnimplementedBrandAlertServiceServer) Preview(context.Context, *BrandAlertRequest) (*PreviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Preview not implemented")
+}
+func (UnimplementedBrandAlertServiceServer) Purchase(context.Context, *BrandAlertRequest) (*BrandAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purchase not implemented")
+}
+func (UnimplementedBrandAlertServiceServer) StreamPurchase(*BrandAlertRequest, BrandAlertService_StreamPurchaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPurchase not implemented")
+}
+func (UnimplementedBrandAlertServiceServer) mustEmbedUnimplementedBrandAlertServiceServer() {}
+
+// UnsafeBrandAlertServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BrandAlertServiceServer will
+// result in compilation errors.
+type UnsafeBrandAlertServiceServer interface {
+	mustEmbedUnimplementedBrandAlertServiceServer()
+}
+
+func RegisterBrandAlertServiceServer(s grpc.ServiceRegistrar, srv BrandAlertServiceServer) {
+	s.RegisterService(&BrandAlertService_ServiceDesc, srv)
+}
+
+func _BrandAlertService_Preview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrandAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrandAlertServiceServer).Preview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/brandalert.v1.BrandAlertService/Preview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrandAlertServiceServer).Preview(ctx, req.(*BrandAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrandAlertService_Purchase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrandAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrandAlertServiceServer).Purchase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/brandalert.v1.BrandAlertService/Purchase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrandAlertServiceServer).Purchase(ctx, req.(*BrandAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrandAlertService_StreamPurchase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BrandAlertRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BrandAlertServiceServer).StreamPurchase(m, &brandAlertServiceStreamPurchaseServer{stream})
+}
+
+type BrandAlertService_StreamPurchaseServer interface {
+	Send(*DomainItem) error
+	grpc.ServerStream
+}
+
+type brandAlertServiceStreamPurchaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *brandAlertServiceStreamPurchaseServer) Send(m *DomainItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BrandAlertService_ServiceDesc is the grpc.ServiceDesc for BrandAlertService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BrandAlertService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "brandalert.v1.BrandAlertService",
+	HandlerType: (*BrandAlertServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Preview",
+			Handler:    _BrandAlertService_Preview_Handler,
+		},
+		{
+			MethodName: "Purchase",
+			Handler:    _BrandAlertService_Purchase_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPurchase",
+			Handler:       _BrandAlertService_StreamPurchase_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/brandalertpb/brand_alert.proto",
+}
@@ -0,0 +1,5 @@
+// Package brandalertpb contains the protobuf messages and the gRPC service of
+// Brand Alert API generated from brand_alert.proto.
+package brandalertpb
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=module=github.com/whois-api-llc/brand-alert-go --go-grpc_out=../.. --go-grpc_opt=module=github.com/whois-api-llc/brand-alert-go rpc/brandalertpb/brand_alert.proto
@@ -0,0 +1,17 @@
+module github.com/whois-api-llc/brand-alert-go/rpc
+
+go 1.17
+
+require (
+	github.com/whois-api-llc/brand-alert-go v0.0.0-20261018211432-1a2e95b37fd6
+	google.golang.org/grpc v1.51.0
+	google.golang.org/protobuf v1.28.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.2 // indirect
+	golang.org/x/net v0.0.0-20220722155237-a158d28d115b // indirect
+	golang.org/x/sys v0.4.0 // indirect
+	golang.org/x/text v0.4.0 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+)
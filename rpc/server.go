@@ -0,0 +1,233 @@
+// Package rpc serves Brand Alert API over gRPC. The server adapts the
+// BrandAlertService of the brandalertpb package onto the BrandAlert interface
+// so the API key stays in the server.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/rpc/brandalertpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// dateFormat is the format of the dates in the messages.
+const dateFormat = "2006-01-02"
+
+// DomainsCountHeader is the header metadata key of the number of domains
+// sent before the items of StreamPurchase.
+const DomainsCountHeader = "x-domains-count"
+
+// Server implements brandalertpb.BrandAlertServiceServer.
+type Server struct {
+	brandalertpb.UnimplementedBrandAlertServiceServer
+
+	client brandalert.BrandAlert
+}
+
+var _ brandalertpb.BrandAlertServiceServer = &Server{}
+
+// NewServer creates Server querying the client.
+func NewServer(client brandalert.BrandAlert) *Server {
+	return &Server{client: client}
+}
+
+// Register registers the server of the client on the gRPC server.
+func Register(s *grpc.Server, client brandalert.BrandAlert) {
+	brandalertpb.RegisterBrandAlertServiceServer(s, NewServer(client))
+}
+
+// Preview returns only the number of domains. No credits deducted.
+func (s *Server) Preview(ctx context.Context, req *brandalertpb.BrandAlertRequest) (*brandalertpb.PreviewResponse, error) {
+	include, exclude, opts, err := arguments(req)
+	if err != nil {
+		return nil, Status(err)
+	}
+
+	count, _, err := s.client.Preview(ctx, include, exclude, opts...)
+	if err != nil {
+		return nil, Status(err)
+	}
+
+	return &brandalertpb.PreviewResponse{DomainsCount: int32(count)}, nil
+}
+
+// Purchase returns the matching domains.
+func (s *Server) Purchase(ctx context.Context, req *brandalertpb.BrandAlertRequest) (*brandalertpb.BrandAlertResponse, error) {
+	resp, err := s.purchase(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*brandalertpb.DomainItem, 0, len(resp.DomainsList))
+	for _, item := range resp.DomainsList {
+		items = append(items, DomainItem(item))
+	}
+
+	return &brandalertpb.BrandAlertResponse{DomainsList: items, DomainsCount: int32(resp.DomainsCount)}, nil
+}
+
+// StreamPurchase sends the matching domains one by one. The number of
+// domains is sent in the DomainsCountHeader header.
+func (s *Server) StreamPurchase(req *brandalertpb.BrandAlertRequest, stream brandalertpb.BrandAlertService_StreamPurchaseServer) error {
+	resp, err := s.purchase(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	header := metadata.Pairs(DomainsCountHeader, strconv.Itoa(resp.DomainsCount))
+	if err := stream.SendHeader(header); err != nil {
+		return err
+	}
+
+	for _, item := range resp.DomainsList {
+		if err := stream.Send(DomainItem(item)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// purchase runs the purchase query of the request.
+func (s *Server) purchase(ctx context.Context, req *brandalertpb.BrandAlertRequest) (*brandalert.BrandAlertResponse, error) {
+	include, exclude, opts, err := arguments(req)
+	if err != nil {
+		return nil, Status(err)
+	}
+
+	resp, _, err := s.client.Purchase(ctx, include, exclude, opts...)
+	if err != nil {
+		return nil, Status(err)
+	}
+
+	return resp, nil
+}
+
+// arguments returns the search terms and options of the request.
+func arguments(req *brandalertpb.BrandAlertRequest) (include, exclude *brandalert.SearchTerms, opts []brandalert.Option, err error) {
+	include = brandalert.SearchTerms(req.IncludeSearchTerms).OrNil()
+	exclude = brandalert.SearchTerms(req.ExcludeSearchTerms).OrNil()
+
+	if err := brandalert.ValidateSearchTerms(include, exclude); err != nil {
+		return nil, nil, nil, err
+	}
+
+	opts = append(opts, brandalert.OptionWithTypos(req.WithTypos))
+
+	if req.SinceDate != "" {
+		date, err := time.Parse(dateFormat, req.SinceDate)
+		if err != nil {
+			return nil, nil, nil, &brandalert.ArgError{Name: "since_date", Message: "must be a date in the YYYY-MM-DD format."}
+		}
+		opts = append(opts, brandalert.OptionSinceDate(date))
+	}
+
+	if req.Punycode != nil {
+		opts = append(opts, brandalert.OptionPunycode(*req.Punycode))
+	}
+
+	return include, exclude, opts, nil
+}
+
+// actions maps the actions to the message actions.
+var actions = map[brandalert.Action]brandalertpb.Action{
+	brandalert.Added:      brandalertpb.Action_ACTION_ADDED,
+	brandalert.Updated:    brandalertpb.Action_ACTION_UPDATED,
+	brandalert.Dropped:    brandalertpb.Action_ACTION_DROPPED,
+	brandalert.Discovered: brandalertpb.Action_ACTION_DISCOVERED,
+}
+
+// DomainItem converts the domain item to the message. Unknown actions are
+// converted to ACTION_UNSPECIFIED.
+func DomainItem(item brandalert.DomainItem) *brandalertpb.DomainItem {
+	msg := &brandalertpb.DomainItem{
+		DomainName: item.DomainName,
+		Action:     actions[item.Action],
+	}
+
+	if !time.Time(item.Date).IsZero() {
+		msg.Date = time.Time(item.Date).Format(dateFormat)
+	}
+
+	return msg
+}
+
+// Status converts the client error to the gRPC status error:
+//
+//	*brandalert.ArgError            InvalidArgument
+//	*brandalert.ErrorMessage        by the API error code, see HTTPCode
+//	*brandalert.ErrorResponse       by the HTTP status code, see HTTPCode
+//	*brandalert.UnknownActionError  Internal
+//	context errors                  Canceled, DeadlineExceeded
+//	network errors                  Unavailable
+func Status(err error) error {
+	// Nil and status errors are returned as is.
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var (
+		argErr    *brandalert.ArgError
+		msgErr    *brandalert.ErrorMessage
+		respErr   *brandalert.ErrorResponse
+		actionErr *brandalert.UnknownActionError
+		netErr    net.Error
+	)
+
+	switch {
+	case errors.As(err, &argErr):
+		return status.Error(codes.InvalidArgument, argErr.Error())
+	case errors.As(err, &msgErr):
+		return status.Error(HTTPCode(msgErr.Code), strings.Join(msgErr.Message, "; "))
+	case errors.As(err, &respErr):
+		return status.Error(HTTPCode(respErr.Response.StatusCode), respErr.Error())
+	case errors.As(err, &actionErr):
+		return status.Error(codes.Internal, actionErr.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.As(err, &netErr):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// HTTPCode returns the gRPC code of the Brand Alert API error or HTTP status code.
+func HTTPCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusPaymentRequired:
+		return codes.ResourceExhausted
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	}
+
+	if code >= 500 && code <= 599 {
+		return codes.Unavailable
+	}
+
+	return codes.Unknown
+}
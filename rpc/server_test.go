@@ -0,0 +1,264 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/rpc/brandalertpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeBrandAlert is the BrandAlert stub returning errors by the first include term.
+type fakeBrandAlert struct{}
+
+var _ brandalert.BrandAlert = fakeBrandAlert{}
+
+// result returns the canned response or error of the term.
+func (fakeBrandAlert) result(include *brandalert.SearchTerms) (*brandalert.BrandAlertResponse, error) {
+	date, _ := brandalert.ParseTime("2022-10-30")
+
+	switch (*include)[0] {
+	case "denied":
+		return nil, &brandalert.ErrorMessage{Code: 403, Message: brandalert.Messages{"Access restricted."}}
+	case "exhausted":
+		return nil, &brandalert.ErrorMessage{Code: 402, Message: brandalert.Messages{"Access restricted. Check credits balance."}}
+	case "busy":
+		return nil, &brandalert.ErrorResponse{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}
+	case "strange":
+		return nil, &brandalert.UnknownActionError{DomainName: "whois.app", Value: "renamed"}
+	case "slow":
+		return nil, context.DeadlineExceeded
+	case "broken":
+		return nil, errors.New("cannot parse response: unexpected EOF")
+	}
+
+	return &brandalert.BrandAlertResponse{
+		DomainsList: []brandalert.DomainItem{
+			{DomainName: "whois-login.com", Action: brandalert.Added, Date: date},
+			{DomainName: "whoisxml.app", Action: brandalert.Dropped, Date: date},
+			{DomainName: "whois.tools", Action: brandalert.Unknown},
+		},
+		DomainsCount: 3,
+	}, nil
+}
+
+// checkTerms validates the terms and rejects the empty exclude terms which
+// would be sent as null.
+func checkTerms(include, exclude *brandalert.SearchTerms) error {
+	if exclude != nil && len(*exclude) == 0 {
+		return errors.New("empty exclude terms must be nil")
+	}
+
+	return brandalert.ValidateSearchTerms(include, exclude)
+}
+
+func (f fakeBrandAlert) Purchase(_ context.Context, include, exclude *brandalert.SearchTerms,
+	_ ...brandalert.Option) (*brandalert.BrandAlertResponse, *brandalert.Response, error) {
+	if err := checkTerms(include, exclude); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := f.result(include)
+	return resp, nil, err
+}
+
+func (f fakeBrandAlert) Preview(_ context.Context, include, exclude *brandalert.SearchTerms,
+	_ ...brandalert.Option) (int, *brandalert.Response, error) {
+	if err := checkTerms(include, exclude); err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := f.result(include)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.DomainsCount, nil, nil
+}
+
+func (fakeBrandAlert) RawData(context.Context, *brandalert.SearchTerms, *brandalert.SearchTerms, ...brandalert.Option) (*brandalert.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+// dial starts the in-process server and returns the client connected to it.
+func dial(t *testing.T) brandalertpb.BrandAlertServiceClient {
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	Register(server, fakeBrandAlert{})
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return brandalertpb.NewBrandAlertServiceClient(conn)
+}
+
+// TestServer tests the unary and streaming RPCs.
+func TestServer(t *testing.T) {
+	client := dial(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"whois"}, SinceDate: "2022-10-30"}
+
+	preview, err := client.Preview(ctx, req)
+	if err != nil || preview.DomainsCount != 3 {
+		t.Fatalf("Preview() got = %v, %v", preview, err)
+	}
+
+	resp, err := client.Purchase(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name   string
+		action brandalertpb.Action
+		date   string
+	}{
+		{name: "whois-login.com", action: brandalertpb.Action_ACTION_ADDED, date: "2022-10-30"},
+		{name: "whoisxml.app", action: brandalertpb.Action_ACTION_DROPPED, date: "2022-10-30"},
+		{name: "whois.tools", action: brandalertpb.Action_ACTION_UNSPECIFIED},
+	}
+
+	if resp.DomainsCount != 3 || len(resp.DomainsList) != len(want) {
+		t.Fatalf("Purchase() got = %v", resp)
+	}
+	for i, w := range want {
+		if got := resp.DomainsList[i]; got.DomainName != w.name || got.Action != w.action || got.Date != w.date {
+			t.Errorf("Purchase() got item = %v, want %+v", got, w)
+		}
+	}
+
+	stream, err := client.StreamPurchase(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := stream.Header()
+	if err != nil || !reflect.DeepEqual(header.Get(DomainsCountHeader), []string{"3"}) {
+		t.Errorf("StreamPurchase() got header = %v, %v", header, err)
+	}
+
+	var names []string
+	for {
+		item, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, item.DomainName)
+	}
+
+	if !reflect.DeepEqual(names, []string{"whois-login.com", "whoisxml.app", "whois.tools"}) {
+		t.Errorf("StreamPurchase() got = %v", names)
+	}
+}
+
+// TestStatus tests mapping the client errors to status codes.
+func TestStatus(t *testing.T) {
+	client := dial(t)
+
+	tests := []struct {
+		name     string
+		req      *brandalertpb.BrandAlertRequest
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{
+			name:     "no terms",
+			req:      &brandalertpb.BrandAlertRequest{},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  `invalid argument: "includeSearchTerms" must have between 1 and 4 items.`,
+		},
+		{
+			name:     "since date",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"whois"}, SinceDate: "yesterday"},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  `invalid argument: "since_date" must be a date in the YYYY-MM-DD format.`,
+		},
+		{
+			name:     "error message",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"denied"}},
+			wantCode: codes.PermissionDenied,
+			wantMsg:  "Access restricted.",
+		},
+		{
+			name:     "credits exhausted",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"exhausted"}},
+			wantCode: codes.ResourceExhausted,
+			wantMsg:  "Access restricted. Check credits balance.",
+		},
+		{
+			name:     "error response",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"busy"}},
+			wantCode: codes.Unavailable,
+			wantMsg:  "API failed with status code: 503",
+		},
+		{
+			name:     "unknown action",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"strange"}},
+			wantCode: codes.Internal,
+			wantMsg:  `unknown action "renamed" of domain "whois.app"`,
+		},
+		{
+			name:     "deadline",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"slow"}},
+			wantCode: codes.DeadlineExceeded,
+			wantMsg:  "context deadline exceeded",
+		},
+		{
+			name:     "other",
+			req:      &brandalertpb.BrandAlertRequest{IncludeSearchTerms: []string{"broken"}},
+			wantCode: codes.Unknown,
+			wantMsg:  "cannot parse response: unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err := client.Purchase(ctx, tt.req)
+			if s := status.Convert(err); s.Code() != tt.wantCode || s.Message() != tt.wantMsg {
+				t.Errorf("Purchase() error = %v, want %v %v", err, tt.wantCode, tt.wantMsg)
+			}
+
+			stream, err := client.StreamPurchase(ctx, tt.req)
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if s := status.Convert(err); s.Code() != tt.wantCode {
+				t.Errorf("StreamPurchase() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+
+	if err := Status(nil); err != nil {
+		t.Errorf("Status(nil) got = %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+// Package schedule parses cron-like expressions describing when brand
+// searches run.
+//
+// Supported expressions are the standard five cron fields (minute, hour,
+// day of month, month and day of week) with lists, ranges, steps and
+// month and weekday names, descriptors like @daily and @hourly, and
+// "@every <duration>".
+package schedule
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule returns the activation times.
+type Schedule interface {
+	// Next returns the first activation time after t.
+	Next(t time.Time) time.Time
+}
+
+// descriptors maps cron descriptors to expressions.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field is the cron field description.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes = field{name: "minute", min: 0, max: 59}
+	hours   = field{name: "hour", min: 0, max: 23}
+	days    = field{name: "day of month", min: 1, max: 31}
+	months  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	weekdays = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Parse parses the schedule expression.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf(`invalid schedule "%s": %w`, spec, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf(`invalid schedule "%s": interval must be positive`, spec)
+		}
+		return Every(d), nil
+	}
+
+	expr := spec
+	if strings.HasPrefix(spec, "@") {
+		var ok bool
+		if expr, ok = descriptors[strings.ToLower(spec)]; !ok {
+			return nil, fmt.Errorf(`invalid schedule "%s": unknown descriptor`, spec)
+		}
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(`invalid schedule "%s": expected 5 fields, got %d`, spec, len(fields))
+	}
+
+	var c cron
+
+	for i, f := range []struct {
+		field
+		bits *uint64
+	}{
+		{minutes, &c.minute},
+		{hours, &c.hour},
+		{days, &c.dom},
+		{months, &c.month},
+		{weekdays, &c.dow},
+	} {
+		bits, err := parseField(fields[i], f.field)
+		if err != nil {
+			return nil, fmt.Errorf(`invalid schedule "%s": %w`, spec, err)
+		}
+		*f.bits = bits
+	}
+
+	// Sunday is both 0 and 7.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	c.domStar = fields[2] == "*"
+	c.dowStar = fields[4] == "*"
+
+	if !c.possible() {
+		return nil, fmt.Errorf(`invalid schedule "%s": day of month never occurs in the months`, spec)
+	}
+
+	return &c, nil
+}
+
+// MustParse is like Parse but panics if the expression can not be parsed.
+func MustParse(spec string) Schedule {
+	s, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// parseField parses the comma separated list of values, ranges and steps.
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+		expr, step := part, 1
+
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			expr = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf(`invalid %s step "%s"`, f.name, part[i+1:])
+			}
+		}
+
+		lo, hi := f.min, f.max
+
+		switch {
+		case expr == "*":
+		case strings.Contains(expr, "-"):
+			i := strings.IndexByte(expr, '-')
+
+			var err error
+			if lo, err = f.value(expr[:i]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(expr[i+1:]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf(`invalid %s range "%s"`, f.name, expr)
+			}
+		default:
+			v, err := f.value(expr)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if strings.Contains(part, "/") {
+				hi = f.max
+			} else {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses the number or the name of the field value.
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf(`invalid %s "%s"`, f.name, s)
+	}
+
+	return v, nil
+}
+
+// cron is the schedule of the cron expression.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// monthDays is the maximum number of days of the months.
+var monthDays = [...]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// possible reports whether the schedule ever activates. It does not if only
+// the day of month is restricted and none of the days occurs in the months,
+// e.g. "0 0 31 2 *".
+func (c *cron) possible() bool {
+	if c.domStar || !c.dowStar {
+		return true
+	}
+
+	for m := 1; m <= 12; m++ {
+		if !has(c.month, m) {
+			continue
+		}
+
+		for d := 1; d <= monthDays[m]; d++ {
+			if has(c.dom, d) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// has reports whether the bit of the value is set.
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+// dayMatches reports whether the day matches the day of month and day of
+// week fields. If both are restricted, either of them has to match.
+func (c *cron) dayMatches(t time.Time) bool {
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+
+	switch {
+	case c.domStar || c.dowStar:
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// Next returns the first matching minute after t in the location of t.
+func (c *cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Every matching time repeats within 5 years (leap day on a weekday).
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(c.minute, t.Minute()) {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// Every is the schedule activating at fixed intervals.
+type Every time.Duration
+
+// Next returns t plus the interval.
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// jitterRand is the source of the jitter seeded per process, so that daemon
+// instances started together do not delay their runs equally. The global
+// source is deterministic before Go 1.20.
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// Jitter returns the time delayed by a random duration in [0, max).
+func Jitter(t time.Time, max time.Duration) time.Time {
+	if max <= 0 {
+		return t
+	}
+
+	jitterMu.Lock()
+	d := jitterRand.Int63n(int64(max))
+	jitterMu.Unlock()
+
+	return t.Add(time.Duration(d))
+}
@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNext tests the activation times of the expressions.
+func TestNext(t *testing.T) {
+	// Tuesday.
+	from := time.Date(2022, 11, 1, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{
+			spec: "*/15 * * * *",
+			want: []string{"2022-11-01T10:45:00Z", "2022-11-01T11:00:00Z"},
+		},
+		{
+			spec: "@daily",
+			want: []string{"2022-11-02T00:00:00Z", "2022-11-03T00:00:00Z"},
+		},
+		{
+			spec: "0 6,18 * * mon-fri",
+			want: []string{"2022-11-01T18:00:00Z", "2022-11-02T06:00:00Z"},
+		},
+		{
+			spec: "30 9 * * 7",
+			want: []string{"2022-11-06T09:30:00Z", "2022-11-13T09:30:00Z"},
+		},
+		{
+			spec: "0 0 13 * fri",
+			want: []string{"2022-11-04T00:00:00Z", "2022-11-11T00:00:00Z", "2022-11-13T00:00:00Z"},
+		},
+		{
+			spec: "0 12 29 feb *",
+			want: []string{"2024-02-29T12:00:00Z"},
+		},
+		{
+			spec: "@every 90m",
+			want: []string{"2022-11-01T12:00:15Z", "2022-11-01T13:30:15Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next := from
+			for _, want := range tt.want {
+				next = s.Next(next)
+				if got := next.Format(time.RFC3339); got != want {
+					t.Errorf("Next() got = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestParse tests the expression errors.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: "* * * *", wantErr: `invalid schedule "* * * *": expected 5 fields, got 4`},
+		{spec: "60 * * * *", wantErr: `invalid schedule "60 * * * *": invalid minute "60"`},
+		{spec: "0 0 * foo *", wantErr: `invalid schedule "0 0 * foo *": invalid month "foo"`},
+		{spec: "*/0 * * * *", wantErr: `invalid schedule "*/0 * * * *": invalid minute step "0"`},
+		{spec: "0 5-1 * * *", wantErr: `invalid schedule "0 5-1 * * *": invalid hour range "5-1"`},
+		{spec: "@often", wantErr: `invalid schedule "@often": unknown descriptor`},
+		{spec: "0 0 31 2,4 *", wantErr: `invalid schedule "0 0 31 2,4 *": day of month never occurs in the months`},
+		{spec: "@every -1h", wantErr: `invalid schedule "@every -1h": interval must be positive`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestJitter tests the Jitter function.
+func TestJitter(t *testing.T) {
+	from := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 100; i++ {
+		if got := Jitter(from, time.Minute); got.Before(from) || !got.Before(from.Add(time.Minute)) {
+			t.Fatalf("Jitter() got = %v", got)
+		}
+	}
+
+	if got := Jitter(from, 0); !got.Equal(from) {
+		t.Errorf("Jitter() got = %v, want %v", got, from)
+	}
+}
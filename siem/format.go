@@ -0,0 +1,215 @@
+// Package siem ships Brand Alert API domains to SIEM systems as CEF or LEEF
+// messages over RFC 5424 syslog. UDP, TCP and TLS transports are supported.
+package siem
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/risk"
+)
+
+// Default device identification of the messages.
+const (
+	DefaultVendor  = "WhoisXML API"
+	DefaultProduct = "Brand Alert"
+	DefaultVersion = "1.0"
+)
+
+// Event is the domain reported to SIEM.
+type Event struct {
+	// Brand is the brand the domain was found for.
+	Brand string
+
+	// Item is the domain returned by Brand Alert API.
+	Item brandalert.DomainItem
+
+	// Score is the domain risk score. It's optional.
+	Score *risk.Score
+}
+
+// NewEvents creates the events of the brand for the items. If the scorer is
+// not nil, the events are scored.
+func NewEvents(brand string, items []brandalert.DomainItem, scorer *risk.Scorer) []Event {
+	events := make([]Event, 0, len(items))
+
+	for _, item := range items {
+		event := Event{Brand: brand, Item: item}
+		if scorer != nil {
+			score := scorer.Score(item)
+			event.Score = &score
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// Severity returns the event severity from 0 to 10 derived from the risk
+// score. Events without the score have severity 5.
+func (e Event) Severity() int {
+	if e.Score == nil {
+		return 5
+	}
+
+	sev := int(math.Round(e.Score.Total * 10 / risk.MaxScore))
+	switch {
+	case sev < 0:
+		return 0
+	case sev > 10:
+		return 10
+	}
+
+	return sev
+}
+
+// reasons returns the comma separated names of the score components.
+func (e Event) reasons() string {
+	if e.Score == nil {
+		return ""
+	}
+
+	names := make([]string, 0, len(e.Score.Components))
+	for _, c := range e.Score.Components {
+		names = append(names, c.Name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Formatter formats events as SIEM messages.
+type Formatter interface {
+	Format(e Event) string
+}
+
+// device is the device identification shared by the message formats.
+type device struct {
+	vendor, product, version string
+}
+
+// newDevice returns the device identification with the default values set.
+func newDevice(vendor, product, version string) device {
+	if vendor == "" {
+		vendor = DefaultVendor
+	}
+	if product == "" {
+		product = DefaultProduct
+	}
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	return device{vendor, product, version}
+}
+
+// CEF formats events in ArcSight Common Event Format. Empty fields are
+// replaced with the default values.
+type CEF struct {
+	Vendor, Product, Version string
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// Format returns the CEF message of the event.
+func (f CEF) Format(e Event) string {
+	d := newDevice(f.Vendor, f.Product, f.Version)
+
+	var b strings.Builder
+
+	header := []string{
+		"CEF:0",
+		d.vendor,
+		d.product,
+		d.version,
+		"domain-" + string(e.Item.Action),
+		"Lookalike domain " + string(e.Item.Action),
+		strconv.Itoa(e.Severity()),
+	}
+	for i, v := range header {
+		if i > 0 {
+			b.WriteByte('|')
+			v = cefHeaderEscaper.Replace(v)
+		}
+		b.WriteString(v)
+	}
+	b.WriteByte('|')
+
+	ext := []string{"dhost", strings.ToLower(e.Item.DomainName), "act", string(e.Item.Action)}
+	if !e.Item.Date.IsZero() {
+		ext = append(ext, "start", strconv.FormatInt(time.Time(e.Item.Date).UnixNano()/int64(time.Millisecond), 10))
+	}
+	if e.Brand != "" {
+		ext = append(ext, "cs1Label", "brand", "cs1", e.Brand)
+	}
+	if e.Score != nil {
+		ext = append(ext,
+			"cn1Label", "riskScore", "cn1", strconv.FormatFloat(e.Score.Total, 'f', -1, 64),
+			"cs2Label", "riskReasons", "cs2", e.reasons())
+	}
+
+	for i := 0; i < len(ext); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(ext[i])
+		b.WriteByte('=')
+		b.WriteString(cefExtensionEscaper.Replace(ext[i+1]))
+	}
+
+	return b.String()
+}
+
+// LEEF formats events in IBM QRadar Log Event Extended Format 1.0 with tab
+// separated attributes. Empty fields are replaced with the default values.
+type LEEF struct {
+	Vendor, Product, Version string
+}
+
+var (
+	leefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	leefAttributeEscaper = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+)
+
+// Format returns the LEEF message of the event.
+func (f LEEF) Format(e Event) string {
+	d := newDevice(f.Vendor, f.Product, f.Version)
+
+	var b strings.Builder
+
+	b.WriteString("LEEF:1.0")
+	for _, v := range []string{d.vendor, d.product, d.version, "domain-" + string(e.Item.Action)} {
+		b.WriteByte('|')
+		b.WriteString(leefHeaderEscaper.Replace(v))
+	}
+	b.WriteByte('|')
+
+	attrs := []string{"cat", string(e.Item.Action), "sev", strconv.Itoa(e.Severity()), "domain", strings.ToLower(e.Item.DomainName)}
+	if !e.Item.Date.IsZero() {
+		attrs = append(attrs, "devTime", e.Item.Date.String(), "devTimeFormat", "yyyy-MM-dd")
+	}
+	if e.Brand != "" {
+		attrs = append(attrs, "brand", e.Brand)
+	}
+	if e.Score != nil {
+		attrs = append(attrs,
+			"riskScore", strconv.FormatFloat(e.Score.Total, 'f', -1, 64),
+			"riskReasons", e.reasons())
+	}
+
+	for i := 0; i < len(attrs); i += 2 {
+		if i > 0 {
+			b.WriteByte('\t')
+		}
+		b.WriteString(attrs[i])
+		b.WriteByte('=')
+		b.WriteString(leefAttributeEscaper.Replace(attrs[i+1]))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package siem
+
+import (
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/risk"
+)
+
+var testItem = brandalert.DomainItem{
+	DomainName: "WhoisXMLAPI-Login.com",
+	Action:     brandalert.Added,
+	Date:       brandalert.Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)),
+}
+
+var testScore = &risk.Score{
+	Item:  testItem,
+	Total: 72.5,
+	Components: []risk.Component{
+		{Name: risk.ComponentTrademark, Points: 40},
+		{Name: risk.ComponentKeyword, Points: 32.5},
+	},
+}
+
+// TestCEF tests the CEF formatter.
+func TestCEF(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter CEF
+		event     Event
+		want      string
+	}{
+		{
+			name:      "scored",
+			formatter: CEF{},
+			event:     Event{Brand: "WhoisXML", Item: testItem, Score: testScore},
+			want: "CEF:0|WhoisXML API|Brand Alert|1.0|domain-added|Lookalike domain added|7|" +
+				"dhost=whoisxmlapi-login.com act=added start=1667088000000 cs1Label=brand cs1=WhoisXML " +
+				"cn1Label=riskScore cn1=72.5 cs2Label=riskReasons cs2=trademark,keyword",
+		},
+		{
+			name:      "escaped",
+			formatter: CEF{Vendor: "ACME|Corp", Product: "Brand\\Watch"},
+			event:     Event{Brand: "A=B\nC", Item: brandalert.DomainItem{DomainName: "whois.com", Action: brandalert.Dropped}},
+			want: `CEF:0|ACME\|Corp|Brand\\Watch|1.0|domain-dropped|Lookalike domain dropped|5|` +
+				`dhost=whois.com act=dropped cs1Label=brand cs1=A\=B\nC`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.Format(tt.event); got != tt.want {
+				t.Errorf("Format() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLEEF tests the LEEF formatter.
+func TestLEEF(t *testing.T) {
+	got := LEEF{}.Format(Event{Brand: "Whois\tXML", Item: testItem, Score: testScore})
+
+	want := "LEEF:1.0|WhoisXML API|Brand Alert|1.0|domain-added|" +
+		"cat=added\tsev=7\tdomain=whoisxmlapi-login.com\tdevTime=2022-10-30\tdevTimeFormat=yyyy-MM-dd\t" +
+		"brand=Whois XML\triskScore=72.5\triskReasons=trademark,keyword"
+
+	if got != want {
+		t.Errorf("Format() got = %q, want %q", got, want)
+	}
+}
+
+// TestNewEvents tests the NewEvents function.
+func TestNewEvents(t *testing.T) {
+	scorer := risk.NewScorer(risk.Profile{Name: "whoisxmlapi"})
+
+	events := NewEvents("WhoisXML", []brandalert.DomainItem{testItem}, scorer)
+	if len(events) != 1 || events[0].Score == nil || events[0].Score.Total <= 0 || events[0].Brand != "WhoisXML" {
+		t.Errorf("NewEvents() got = %+v", events)
+	}
+
+	if events := NewEvents("", []brandalert.DomainItem{testItem}, nil); events[0].Score != nil || events[0].Severity() != 5 {
+		t.Errorf("NewEvents() got = %+v", events)
+	}
+}
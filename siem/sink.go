@@ -0,0 +1,81 @@
+package siem
+
+import (
+	"context"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/risk"
+)
+
+// DefaultBatchSize is the default number of messages sent at once.
+const DefaultBatchSize = 100
+
+// Sink formats new domains as SIEM messages and ships them over syslog.
+type Sink struct {
+	// Syslog is the syslog client.
+	Syslog *Syslog
+
+	// Formatter formats the events. Default: CEF{}.
+	Formatter Formatter
+
+	// Brand is the brand name added to the messages.
+	Brand string
+
+	// Scorer scores the domains. If it's nil, risk fields are omitted.
+	Scorer *risk.Scorer
+
+	// BatchSize is the number of messages sent at once. Default: DefaultBatchSize.
+	BatchSize int
+}
+
+// Send sends a message for every item in batches of BatchSize.
+func (s *Sink) Send(ctx context.Context, items []brandalert.DomainItem) error {
+	return s.SendEvents(ctx, NewEvents(s.Brand, items, s.Scorer))
+}
+
+// SendEvents sends a message for every event in batches of BatchSize.
+func (s *Sink) SendEvents(ctx context.Context, events []Event) error {
+	formatter := s.Formatter
+	if formatter == nil {
+		formatter = CEF{}
+	}
+
+	size := s.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	now := time.Now()
+	batch := make([]Message, 0, size)
+
+	for i, e := range events {
+		batch = append(batch, Message{
+			Severity: syslogSeverity(e.Severity()),
+			Time:     now,
+			MsgID:    "domain-" + string(e.Item.Action),
+			Text:     formatter.Format(e),
+		})
+
+		if len(batch) == size || i == len(events)-1 {
+			if err := s.Syslog.Send(ctx, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	return nil
+}
+
+// syslogSeverity maps the event severity from 0 to 10 to the syslog severity.
+func syslogSeverity(sev int) Severity {
+	switch {
+	case sev >= 7:
+		return SeverityWarning
+	case sev >= 4:
+		return SeverityNotice
+	}
+
+	return SeverityInformational
+}
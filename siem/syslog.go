@@ -0,0 +1,314 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Severity is the syslog message severity.
+type Severity int
+
+// List of syslog severities.
+const (
+	SeverityEmergency Severity = iota
+	SeverityAlert
+	SeverityCritical
+	SeverityError
+	SeverityWarning
+	SeverityNotice
+	SeverityInformational
+	SeverityDebug
+)
+
+// Facility is the syslog facility.
+type Facility int
+
+// List of commonly used syslog facilities.
+const (
+	FacilityUser     Facility = 1
+	FacilityDaemon   Facility = 3
+	FacilitySecurity Facility = 13
+	FacilityLocal0   Facility = 16
+	FacilityLocal1   Facility = 17
+	FacilityLocal2   Facility = 18
+	FacilityLocal3   Facility = 19
+	FacilityLocal4   Facility = 20
+	FacilityLocal5   Facility = 21
+	FacilityLocal6   Facility = 22
+	FacilityLocal7   Facility = 23
+)
+
+// List of supported syslog networks.
+const (
+	UDP = "udp"
+	TCP = "tcp"
+	TLS = "tls"
+)
+
+// timestampFormat is the RFC 5424 timestamp format with microsecond precision.
+const timestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// Message is the syslog message.
+type Message struct {
+	// Severity is the message severity.
+	Severity Severity
+
+	// Time is the message timestamp. Default: the current time.
+	Time time.Time
+
+	// MsgID identifies the type of the message. Default: "-".
+	MsgID string
+
+	// Text is the message text.
+	Text string
+}
+
+// SyslogParams is used to create the syslog client. Only Address is mandatory.
+type SyslogParams struct {
+	// Network is the transport: UDP (default), TCP or TLS. Messages are framed
+	// by octet counting over TCP and TLS.
+	Network string
+
+	// Address is the host:port of the syslog server.
+	Address string
+
+	// TLSConfig is the TLS configuration used with the TLS network.
+	TLSConfig *tls.Config
+
+	// Facility is the syslog facility. Default: FacilityUser.
+	Facility Facility
+
+	// Hostname is the HOSTNAME field. Default: os.Hostname().
+	Hostname string
+
+	// AppName is the APP-NAME field. Default: "brand-alert".
+	AppName string
+
+	// DialTimeout and WriteTimeout limit connecting and writing. Default: 10s.
+	DialTimeout, WriteTimeout time.Duration
+
+	// Retries is the number of reconnection attempts after a failed write.
+	// Default: 3. Negative values disable retries.
+	Retries int
+
+	// RetryInterval is the delay before reconnection. Default: 1s.
+	RetryInterval time.Duration
+}
+
+// Syslog is the RFC 5424 syslog client. It connects lazily and reconnects
+// when the connection is broken. It's safe for concurrent use.
+type Syslog struct {
+	params SyslogParams
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslog creates the syslog client.
+func NewSyslog(params SyslogParams) (*Syslog, error) {
+	switch params.Network {
+	case "":
+		params.Network = UDP
+	case UDP, TCP, TLS:
+	default:
+		return nil, fmt.Errorf("unsupported network %q", params.Network)
+	}
+
+	if params.Address == "" {
+		return nil, errors.New("address can not be empty")
+	}
+
+	if params.Facility == 0 {
+		params.Facility = FacilityUser
+	}
+	if params.Hostname == "" {
+		params.Hostname, _ = os.Hostname()
+	}
+	if params.AppName == "" {
+		params.AppName = "brand-alert"
+	}
+	if params.DialTimeout == 0 {
+		params.DialTimeout = 10 * time.Second
+	}
+	if params.WriteTimeout == 0 {
+		params.WriteTimeout = 10 * time.Second
+	}
+	if params.Retries == 0 {
+		params.Retries = 3
+	}
+	if params.RetryInterval == 0 {
+		params.RetryInterval = time.Second
+	}
+
+	return &Syslog{params: params}, nil
+}
+
+// Format returns the RFC 5424 representation of the message.
+func (s *Syslog) Format(msg Message) string {
+	ts := msg.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		int(s.params.Facility)*8+int(msg.Severity),
+		ts.Format(timestampFormat),
+		header(s.params.Hostname, 255),
+		header(s.params.AppName, 48),
+		os.Getpid(),
+		header(msg.MsgID, 32),
+		msg.Text,
+	)
+}
+
+// Send sends the messages. Over TCP and TLS all messages are written at once.
+// If writing fails, the client reconnects and sends the messages again, so
+// they are delivered at least once.
+func (s *Syslog) Send(ctx context.Context, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	var frames [][]byte
+
+	if s.params.Network == UDP {
+		for _, msg := range msgs {
+			frames = append(frames, []byte(s.Format(msg)))
+		}
+	} else {
+		var b bytes.Buffer
+		for _, msg := range msgs {
+			text := s.Format(msg)
+			b.WriteString(strconv.Itoa(len(text)))
+			b.WriteByte(' ')
+			b.WriteString(text)
+		}
+		frames = append(frames, b.Bytes())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		err := s.write(ctx, frames)
+		if err == nil {
+			return nil
+		}
+
+		s.closeConn()
+
+		if attempt >= s.params.Retries {
+			return fmt.Errorf("cannot send syslog messages: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.params.RetryInterval):
+		}
+	}
+}
+
+// Close closes the connection.
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeConn()
+}
+
+// write writes the frames connecting if needed.
+func (s *Syslog) write(ctx context.Context, frames [][]byte) error {
+	if s.conn != nil && s.params.Network != UDP && !alive(s.conn) {
+		s.closeConn()
+	}
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	for _, frame := range frames {
+		if err := s.conn.SetWriteDeadline(time.Now().Add(s.params.WriteTimeout)); err != nil {
+			return err
+		}
+		if _, err := s.conn.Write(frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dial connects to the syslog server.
+func (s *Syslog) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.params.DialTimeout}
+
+	switch s.params.Network {
+	case TLS:
+		d := &tls.Dialer{NetDialer: dialer, Config: s.params.TLSConfig}
+		return d.DialContext(ctx, "tcp", s.params.Address)
+	default:
+		return dialer.DialContext(ctx, s.params.Network, s.params.Address)
+	}
+}
+
+// closeConn closes the connection if it's open.
+func (s *Syslog) closeConn() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// alive reports whether the stream connection was not closed by the server.
+// Syslog servers never send data, so a read returning anything but a
+// timeout means the connection is broken.
+func alive(conn net.Conn) bool {
+	if err := conn.SetReadDeadline(time.Now().Add(time.Millisecond)); err != nil {
+		return false
+	}
+
+	var b [1]byte
+	_, err := conn.Read(b[:])
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// header returns the RFC 5424 header field of printable ASCII characters
+// limited to max length. Empty values are replaced with the NILVALUE.
+func header(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, s)
+
+	if len(s) > max {
+		s = s[:max]
+	}
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,301 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+var messageRe = regexp.MustCompile(`^<(\d+)>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(Z|[+-]\d{2}:\d{2}) test-host brand-alert-test \d+ (\S+) - (.*)$`)
+
+// readFrames reads octet-counted syslog frames from the connection.
+func readFrames(conn net.Conn, n int) ([]string, error) {
+	r := bufio.NewReader(conn)
+
+	var frames []string
+
+	for len(frames) < n {
+		prefix, err := r.ReadString(' ')
+		if err != nil {
+			return frames, err
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(prefix))
+		if err != nil {
+			return frames, err
+		}
+
+		b := make([]byte, size)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return frames, err
+		}
+
+		frames = append(frames, string(b))
+	}
+
+	return frames, nil
+}
+
+func testParams(network, addr string) SyslogParams {
+	return SyslogParams{
+		Network:       network,
+		Address:       addr,
+		Facility:      FacilityLocal0,
+		Hostname:      "test-host",
+		AppName:       "brand-alert-test",
+		RetryInterval: 10 * time.Millisecond,
+	}
+}
+
+func testMessages(texts ...string) []Message {
+	msgs := make([]Message, 0, len(texts))
+	for _, text := range texts {
+		msgs = append(msgs, Message{Severity: SeverityWarning, MsgID: "test", Text: text})
+	}
+
+	return msgs
+}
+
+// checkMessage checks the syslog message format, priority and text.
+func checkMessage(t *testing.T, msg, text string) {
+	m := messageRe.FindStringSubmatch(msg)
+	if m == nil {
+		t.Errorf("invalid syslog message %q", msg)
+		return
+	}
+
+	if m[1] != "132" || m[3] != "test" || m[4] != text {
+		t.Errorf("got message %q, want text %q", msg, text)
+	}
+}
+
+// TestSyslogUDP tests sending messages over UDP.
+func TestSyslogUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	s, err := NewSyslog(testParams(UDP, pc.LocalAddr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Send(context.Background(), testMessages("first", "second")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pc.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 2048)
+	for _, text := range []string{"first", "second"} {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkMessage(t, string(buf[:n]), text)
+	}
+}
+
+// TestSyslogTCPReconnect tests reconnecting after the server closes the connection.
+func TestSyslogTCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	type result struct {
+		frames []string
+		err    error
+	}
+
+	results := make(chan result)
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				results <- result{err: err}
+				return
+			}
+
+			frames, err := readFrames(conn, 2)
+			conn.Close()
+			results <- result{frames, err}
+		}
+	}()
+
+	s, err := NewSyslog(testParams(TCP, ln.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for _, batch := range [][]string{{"first", "second"}, {"third", "fourth"}} {
+		if err := s.Send(context.Background(), testMessages(batch...)); err != nil {
+			t.Fatal(err)
+		}
+
+		res := <-results
+		if res.err != nil {
+			t.Fatal(res.err)
+		}
+
+		for i, frame := range res.frames {
+			checkMessage(t, frame, batch[i])
+		}
+	}
+}
+
+// TestSyslogTLS tests sending messages over TLS.
+func TestSyslogTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(nil)
+	server.StartTLS()
+	defer server.Close()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: server.TLS.Certificates})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	frames := make(chan []string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			frames <- nil
+			return
+		}
+		defer conn.Close()
+
+		f, _ := readFrames(conn, 1)
+		frames <- f
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	params := testParams(TLS, ln.Addr().String())
+	params.TLSConfig = &tls.Config{RootCAs: pool}
+
+	s, err := NewSyslog(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Send(context.Background(), testMessages("secure")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-frames
+	if len(got) != 1 {
+		t.Fatalf("got %d frames, want 1", len(got))
+	}
+	checkMessage(t, got[0], "secure")
+}
+
+// TestSyslogErrors tests the syslog client errors.
+func TestSyslogErrors(t *testing.T) {
+	_, err := NewSyslog(SyslogParams{Network: "unix", Address: "/dev/log"})
+	checkErr(t, err, `unsupported network "unix"`)
+
+	_, err = NewSyslog(SyslogParams{})
+	checkErr(t, err, "address can not be empty")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	params := testParams(TCP, addr)
+	params.Retries = 1
+
+	s, err := NewSyslog(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Send(context.Background(), testMessages("lost")); err == nil {
+		t.Errorf("Send() expected error for closed server")
+	}
+}
+
+// TestSink tests sending domains in batches.
+func TestSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	frames := make(chan []string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			frames <- nil
+			return
+		}
+		defer conn.Close()
+
+		f, _ := readFrames(conn, 3)
+		frames <- f
+	}()
+
+	s, err := NewSyslog(testParams(TCP, ln.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	sink := &Sink{Syslog: s, Formatter: LEEF{}, Brand: "WhoisXML", BatchSize: 2}
+
+	items := []brandalert.DomainItem{
+		{DomainName: "whois1.com", Action: brandalert.Added},
+		{DomainName: "whois2.com", Action: brandalert.Dropped},
+		{DomainName: "whois3.com", Action: brandalert.Updated},
+	}
+
+	if err := sink.Send(context.Background(), items); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-frames
+	if len(got) != 3 {
+		t.Fatalf("got %d frames, want 3", len(got))
+	}
+
+	for i, frame := range got {
+		want := "<133>1 "
+		if !strings.HasPrefix(frame, want) || !strings.Contains(frame, " domain-"+string(items[i].Action)+" - LEEF:1.0|") ||
+			!strings.Contains(frame, "domain="+items[i].DomainName+"\t") {
+			t.Errorf("got frame %q", frame)
+		}
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
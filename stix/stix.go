@@ -0,0 +1,350 @@
+// Package stix exports Brand Alert API domains as STIX 2.1 bundles of
+// domain-name observables and indicators. Identifiers are deterministic,
+// so repeated exports of the same domains produce the same objects.
+package stix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/internal/uuid"
+)
+
+// SpecVersion is the STIX specification version.
+const SpecVersion = "2.1"
+
+// timestampFormat is the STIX timestamp format with millisecond precision.
+const timestampFormat = "2006-01-02T15:04:05.000Z"
+
+var (
+	// scoNamespace is the namespace of the STIX Cyber-observable Object identifiers.
+	scoNamespace = uuid.MustParse("00abedb4-aa42-5fe4-9d8a-ae3ef5b6d0d0")
+
+	// sdoNamespace is the namespace of the identifiers of the objects created by this package.
+	sdoNamespace = uuid.MustParse("494fa95e-4a26-460a-9c44-e3dece8309c0")
+)
+
+// Timestamp is the STIX timestamp.
+type Timestamp time.Time
+
+// MarshalJSON encodes the timestamp in UTC with millisecond precision.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Time(t).UTC().Format(timestampFormat) + `"`), nil
+}
+
+// UnmarshalJSON decodes the RFC 3339 timestamp.
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+
+	*t = Timestamp(v)
+
+	return nil
+}
+
+// DomainName is the STIX domain-name Cyber-observable Object.
+type DomainName struct {
+	Type        string `json:"type"`
+	SpecVersion string `json:"spec_version"`
+	ID          string `json:"id"`
+	Value       string `json:"value"`
+}
+
+// Indicator is the STIX indicator Domain Object.
+type Indicator struct {
+	Type           string    `json:"type"`
+	SpecVersion    string    `json:"spec_version"`
+	ID             string    `json:"id"`
+	Created        Timestamp `json:"created"`
+	Modified       Timestamp `json:"modified"`
+	Name           string    `json:"name,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	IndicatorTypes []string  `json:"indicator_types,omitempty"`
+	Pattern        string    `json:"pattern"`
+	PatternType    string    `json:"pattern_type"`
+	PatternVersion string    `json:"pattern_version,omitempty"`
+	ValidFrom      Timestamp `json:"valid_from"`
+	Labels         []string  `json:"labels,omitempty"`
+
+	// Custom properties with the Brand Alert API search metadata.
+	IncludeSearchTerms []string `json:"x_brand_alert_include_search_terms,omitempty"`
+	ExcludeSearchTerms []string `json:"x_brand_alert_exclude_search_terms,omitempty"`
+	SinceDate          string   `json:"x_brand_alert_since_date,omitempty"`
+	WithTypos          bool     `json:"x_brand_alert_with_typos,omitempty"`
+	Action             string   `json:"x_brand_alert_action,omitempty"`
+}
+
+// Relationship is the STIX relationship object.
+type Relationship struct {
+	Type             string    `json:"type"`
+	SpecVersion      string    `json:"spec_version"`
+	ID               string    `json:"id"`
+	Created          Timestamp `json:"created"`
+	Modified         Timestamp `json:"modified"`
+	RelationshipType string    `json:"relationship_type"`
+	SourceRef        string    `json:"source_ref"`
+	TargetRef        string    `json:"target_ref"`
+}
+
+// Bundle is the STIX bundle.
+type Bundle struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id"`
+	Objects []interface{} `json:"objects"`
+}
+
+// Metadata is the Brand Alert API search the domains were found by.
+type Metadata struct {
+	// Brand is the brand name used in indicator names and descriptions.
+	Brand string
+
+	// IncludeSearchTerms is the list of including search terms.
+	IncludeSearchTerms brandalert.SearchTerms
+
+	// ExcludeSearchTerms is the list of excluding search terms.
+	ExcludeSearchTerms brandalert.SearchTerms
+
+	// SinceDate is the date the search was made since.
+	SinceDate time.Time
+
+	// WithTypos is true if the search terms were enriched with typos.
+	WithTypos bool
+}
+
+// Params is used to create bundles. None of parameters are mandatory.
+type Params struct {
+	// Metadata is the search metadata added to the indicators.
+	Metadata Metadata
+
+	// IndicatorTypes is the list of indicator types. Default: ["anomalous-activity"].
+	IndicatorTypes []string
+
+	// Created is the creation time of the objects. Default: the indicator
+	// valid_from time, so repeated exports of the same domain produce
+	// identical objects.
+	Created time.Time
+}
+
+// NewBundle creates the bundle with a domain-name observable, an indicator and
+// a relationship between them for every unique domain. Indicators are valid
+// from the domain date and labeled with the domain action.
+func NewBundle(items []brandalert.DomainItem, params Params) *Bundle {
+	meta := params.Metadata
+
+	created := params.Created.UTC().Truncate(time.Millisecond)
+
+	// undated is the valid_from time of the domains without a date.
+	undated := created
+	if undated.IsZero() {
+		undated = meta.SinceDate.UTC()
+	}
+	if undated.IsZero() {
+		undated = time.Now().UTC().Truncate(time.Millisecond)
+	}
+
+	indicatorTypes := params.IndicatorTypes
+	if len(indicatorTypes) == 0 {
+		indicatorTypes = []string{"anomalous-activity"}
+	}
+
+	var sinceDate string
+	if !meta.SinceDate.IsZero() {
+		sinceDate = meta.SinceDate.Format("2006-01-02")
+	}
+
+	bundle := &Bundle{
+		Type:    "bundle",
+		Objects: []interface{}{},
+	}
+
+	ids := make([]string, 0, 3*len(items))
+
+	for _, item := range unique(items) {
+		domain := strings.ToLower(item.DomainName)
+
+		observable := DomainName{
+			Type:        "domain-name",
+			SpecVersion: SpecVersion,
+			ID:          "domain-name--" + uuid.NewV5(scoNamespace, canonicalValue(domain)).String(),
+			Value:       domain,
+		}
+
+		// The indicator ID of the domains without a date is derived from the
+		// action, since their valid_from time may vary between exports.
+		validFrom, idPart := undated, string(item.Action)
+		if !item.Date.IsZero() {
+			validFrom = time.Time(item.Date).UTC()
+			idPart = validFrom.Format(timestampFormat)
+		}
+
+		objCreated := created
+		if objCreated.IsZero() {
+			objCreated = validFrom
+		}
+
+		pattern := "[domain-name:value = '" + escapePattern(domain) + "']"
+
+		indicator := Indicator{
+			Type:               "indicator",
+			SpecVersion:        SpecVersion,
+			ID:                 "indicator--" + sdoID("indicator", pattern, idPart),
+			Created:            Timestamp(objCreated),
+			Modified:           Timestamp(objCreated),
+			Name:               indicatorName(meta.Brand, domain),
+			Description:        description(meta, item),
+			IndicatorTypes:     indicatorTypes,
+			Pattern:            pattern,
+			PatternType:        "stix",
+			PatternVersion:     SpecVersion,
+			ValidFrom:          Timestamp(validFrom),
+			Labels:             labels(item.Action),
+			IncludeSearchTerms: terms(meta.IncludeSearchTerms),
+			ExcludeSearchTerms: terms(meta.ExcludeSearchTerms),
+			SinceDate:          sinceDate,
+			WithTypos:          meta.WithTypos,
+			Action:             string(item.Action),
+		}
+
+		relationship := Relationship{
+			Type:             "relationship",
+			SpecVersion:      SpecVersion,
+			ID:               "relationship--" + sdoID("based-on", indicator.ID, observable.ID),
+			Created:          Timestamp(objCreated),
+			Modified:         Timestamp(objCreated),
+			RelationshipType: "based-on",
+			SourceRef:        indicator.ID,
+			TargetRef:        observable.ID,
+		}
+
+		bundle.Objects = append(bundle.Objects, observable, indicator, relationship)
+		ids = append(ids, observable.ID, indicator.ID, relationship.ID)
+	}
+
+	sort.Strings(ids)
+	bundle.ID = "bundle--" + sdoID(append([]string{"bundle"}, ids...)...)
+
+	return bundle
+}
+
+// Write writes the bundle of the domains as JSON.
+func Write(w io.Writer, items []brandalert.DomainItem, params Params) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(NewBundle(items, params)); err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+
+	return nil
+}
+
+// unique returns the items with unique lower case domain names keeping the latest event.
+func unique(items []brandalert.DomainItem) []brandalert.DomainItem {
+	index := make(map[string]int, len(items))
+	res := make([]brandalert.DomainItem, 0, len(items))
+
+	for _, item := range items {
+		name := strings.ToLower(item.DomainName)
+		if i, ok := index[name]; ok {
+			if item.Date.After(res[i].Date) {
+				res[i] = item
+			}
+			continue
+		}
+
+		index[name] = len(res)
+		res = append(res, item)
+	}
+
+	return res
+}
+
+// sdoID returns the deterministic identifier of the object made of the parts.
+func sdoID(parts ...string) string {
+	return uuid.NewV5(sdoNamespace, strings.Join(parts, "|")).String()
+}
+
+// canonicalValue returns the canonical JSON of the domain-name ID contributing property.
+func canonicalValue(domain string) string {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(map[string]string{"value": domain})
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// escapePattern escapes the string for the STIX patterning language.
+func escapePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
+
+// labels returns the labels of the indicator.
+func labels(action brandalert.Action) []string {
+	res := []string{"brand-alert"}
+	if action != "" {
+		res = append(res, "brand-alert-"+string(action))
+	}
+
+	return res
+}
+
+// indicatorName returns the name of the indicator.
+func indicatorName(brand, domain string) string {
+	if brand == "" {
+		return "Lookalike domain " + domain
+	}
+
+	return brand + " lookalike domain " + domain
+}
+
+// description returns the description of the indicator.
+func description(meta Metadata, item brandalert.DomainItem) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Domain %s", strings.ToLower(item.DomainName))
+	if item.Action != "" {
+		fmt.Fprintf(&b, " %s", item.Action)
+	}
+	if !item.Date.IsZero() {
+		fmt.Fprintf(&b, " on %s", item.Date)
+	}
+
+	if len(meta.IncludeSearchTerms) > 0 {
+		fmt.Fprintf(&b, ", found by Brand Alert API search for %s", strings.Join(meta.IncludeSearchTerms, ", "))
+		if len(meta.ExcludeSearchTerms) > 0 {
+			fmt.Fprintf(&b, " excluding %s", strings.Join(meta.ExcludeSearchTerms, ", "))
+		}
+		if meta.WithTypos {
+			b.WriteString(" with typos")
+		}
+	}
+
+	b.WriteString(".")
+
+	return b.String()
+}
+
+// terms returns the search terms as a slice.
+func terms(t brandalert.SearchTerms) []string {
+	if len(t) == 0 {
+		return nil
+	}
+
+	return []string(t)
+}
@@ -0,0 +1,209 @@
+package stix
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+// requiredProperties is the list of required properties per STIX 2.1 object type.
+var requiredProperties = map[string][]string{
+	"bundle":       {"type", "id"},
+	"domain-name":  {"type", "id", "value"},
+	"indicator":    {"type", "spec_version", "id", "created", "modified", "pattern", "pattern_type", "valid_from"},
+	"relationship": {"type", "spec_version", "id", "created", "modified", "relationship_type", "source_ref", "target_ref"},
+}
+
+var (
+	idRe        = regexp.MustCompile(`^([a-z0-9-]+)--[0-9a-f]{8}-[0-9a-f]{4}-[45][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	timestampRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$`)
+	propertyRe  = regexp.MustCompile(`^[a-z0-9_]{3,250}$`)
+)
+
+// validate checks the object against the STIX 2.1 required properties.
+func validate(t *testing.T, obj map[string]interface{}) {
+	typ, _ := obj["type"].(string)
+
+	required, ok := requiredProperties[typ]
+	if !ok {
+		t.Fatalf("unexpected object type %q", typ)
+	}
+
+	for _, p := range required {
+		if v, ok := obj[p]; !ok || v == "" {
+			t.Errorf("%s: required property %q is missing", typ, p)
+		}
+	}
+
+	if m := idRe.FindStringSubmatch(obj["id"].(string)); m == nil || m[1] != typ {
+		t.Errorf("%s: invalid id %v", typ, obj["id"])
+	}
+
+	if typ != "bundle" && obj["spec_version"] != SpecVersion {
+		t.Errorf("%s: invalid spec_version %v", typ, obj["spec_version"])
+	}
+
+	for _, p := range []string{"created", "modified", "valid_from"} {
+		if v, ok := obj[p]; ok && !timestampRe.MatchString(v.(string)) {
+			t.Errorf("%s: invalid timestamp %s = %v", typ, p, v)
+		}
+	}
+
+	for p := range obj {
+		if p != "id" && !propertyRe.MatchString(p) {
+			t.Errorf("%s: invalid property name %q", typ, p)
+		}
+	}
+}
+
+// TestWrite tests the Write function.
+func TestWrite(t *testing.T) {
+	items := []brandalert.DomainItem{
+		{DomainName: "WhoisDodster.com", Action: brandalert.Added, Date: brandalert.Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+		{DomainName: "who'is.com", Action: brandalert.Dropped},
+		{DomainName: "whoisdodster.com", Action: brandalert.Added, Date: brandalert.Time(time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC))},
+	}
+
+	params := Params{
+		Metadata: Metadata{
+			Brand:              "WhoisXML",
+			IncludeSearchTerms: brandalert.SearchTerms{"whois"},
+			ExcludeSearchTerms: brandalert.SearchTerms{"api"},
+			SinceDate:          time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+			WithTypos:          true,
+		},
+		Created: time.Date(2022, 11, 1, 12, 30, 0, 123456789, time.UTC),
+	}
+
+	var b1, b2 bytes.Buffer
+
+	if err := Write(&b1, items, params); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(&b2, items, params); err != nil {
+		t.Fatal(err)
+	}
+
+	if b1.String() != b2.String() {
+		t.Errorf("Write() is not deterministic")
+	}
+
+	var bundle struct {
+		Type    string                   `json:"type"`
+		ID      string                   `json:"id"`
+		Objects []map[string]interface{} `json:"objects"`
+	}
+
+	if err := json.Unmarshal(b1.Bytes(), &bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	validate(t, map[string]interface{}{"type": bundle.Type, "id": bundle.ID})
+
+	if len(bundle.Objects) != 6 {
+		t.Fatalf("Write() got %d objects, want 6", len(bundle.Objects))
+	}
+
+	for _, obj := range bundle.Objects {
+		validate(t, obj)
+	}
+
+	domain := bundle.Objects[0]
+	if domain["id"] != "domain-name--574f83e7-1dfc-5103-95b6-b1a77e900d9b" || domain["value"] != "whoisdodster.com" {
+		t.Errorf("Write() got domain-name = %v", domain)
+	}
+
+	indicator := bundle.Objects[1]
+
+	want := map[string]interface{}{
+		"pattern":                  "[domain-name:value = 'whoisdodster.com']",
+		"valid_from":               "2022-10-30T00:00:00.000Z",
+		"created":                  "2022-11-01T12:30:00.123Z",
+		"name":                     "WhoisXML lookalike domain whoisdodster.com",
+		"description":              "Domain whoisdodster.com added on 2022-10-30, found by Brand Alert API search for whois excluding api with typos.",
+		"x_brand_alert_since_date": "2022-10-01",
+		"x_brand_alert_with_typos": true,
+	}
+	for k, v := range want {
+		if indicator[k] != v {
+			t.Errorf("Write() got indicator %s = %v, want %v", k, indicator[k], v)
+		}
+	}
+
+	if labels, _ := json.Marshal(indicator["labels"]); string(labels) != `["brand-alert","brand-alert-added"]` {
+		t.Errorf("Write() got labels = %s", labels)
+	}
+
+	relationship := bundle.Objects[2]
+	if relationship["source_ref"] != indicator["id"] || relationship["target_ref"] != domain["id"] {
+		t.Errorf("Write() got relationship = %v", relationship)
+	}
+
+	escaped := bundle.Objects[4]
+	if escaped["pattern"] != `[domain-name:value = 'who\'is.com']` || escaped["valid_from"] != "2022-11-01T12:30:00.123Z" {
+		t.Errorf("Write() got indicator = %v", escaped)
+	}
+}
+
+// TestNewBundleDeterministic tests that repeated exports of the same domains are identical.
+func TestNewBundleDeterministic(t *testing.T) {
+	items := []brandalert.DomainItem{
+		{DomainName: "whoisdodster.com", Date: brandalert.Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)), Action: brandalert.Added},
+		{DomainName: "whoisfinder.net", Action: brandalert.Dropped},
+	}
+	params := Params{Metadata: Metadata{Brand: "WhoisXML", SinceDate: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)}}
+
+	var first, second bytes.Buffer
+
+	if err := Write(&first, items, params); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := Write(&second, items, params); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.String() != second.String() {
+		t.Errorf("Write() exports differ:\n%s\n%s", first.String(), second.String())
+	}
+
+	var bundle struct {
+		Objects []map[string]interface{} `json:"objects"`
+	}
+	if err := json.Unmarshal(first.Bytes(), &bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	if created := bundle.Objects[1]["created"]; created != "2022-10-30T00:00:00.000Z" {
+		t.Errorf("Write() got indicator created = %v, want the domain date", created)
+	}
+	if created := bundle.Objects[4]["created"]; created != "2022-10-01T00:00:00.000Z" {
+		t.Errorf("Write() got undated indicator created = %v, want the since date", created)
+	}
+}
+
+// TestNewBundleUndated tests that the indicator IDs of the domains without a
+// date are derived from the domain and the action.
+func TestNewBundleUndated(t *testing.T) {
+	indicatorID := func(action brandalert.Action, params Params) string {
+		bundle := NewBundle([]brandalert.DomainItem{{DomainName: "whoisfinder.net", Action: action}}, params)
+		return bundle.Objects[1].(Indicator).ID
+	}
+
+	first := indicatorID(brandalert.Added, Params{})
+	time.Sleep(2 * time.Millisecond)
+
+	if second := indicatorID(brandalert.Added, Params{}); second != first {
+		t.Errorf("NewBundle() got ID = %v, want %v", second, first)
+	}
+	if other := indicatorID(brandalert.Added, Params{Created: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)}); other != first {
+		t.Errorf("NewBundle() got ID = %v with Created, want %v", other, first)
+	}
+	if dropped := indicatorID(brandalert.Dropped, Params{}); dropped == first {
+		t.Errorf("NewBundle() got the same ID = %v for different actions", dropped)
+	}
+}
@@ -0,0 +1,257 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultBucket is the bucket of the stores opened by OpenBolt.
+const defaultBucket = "store"
+
+// Bolt is the Store keeping the values in the bucket of the bbolt database.
+// Values are prefixed with their expiration time in Unix nanoseconds, zero
+// if they never expire.
+type Bolt struct {
+	db     *bolt.DB
+	bucket []byte
+	owned  bool
+
+	mu     sync.RWMutex
+	closed bool
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+var _ Store = &Bolt{}
+
+// OpenBolt opens or creates the bbolt database file. Close closes the database.
+func OpenBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open database: %w", err)
+	}
+
+	b, err := NewBolt(db, defaultBucket)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	b.owned = true
+
+	return b, nil
+}
+
+// NewBolt creates the store in the bucket of the open database, for example
+// one shared with the history package. Close does not close the database.
+func NewBolt(db *bolt.DB, bucket string) (*Bolt, error) {
+	if bucket == "" {
+		return nil, errors.New("bucket can not be empty")
+	}
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create bucket: %w", err)
+	}
+
+	return &Bolt{db: db, bucket: []byte(bucket)}, nil
+}
+
+// now returns the current time.
+func (b *Bolt) now() time.Time {
+	if b.Now != nil {
+		return b.Now()
+	}
+	return time.Now()
+}
+
+// encode returns the stored value of the entry.
+func encode(e entry) []byte {
+	v := make([]byte, 8+len(e.Value))
+
+	if !e.Expires.IsZero() {
+		binary.BigEndian.PutUint64(v, uint64(e.Expires.UnixNano()))
+	}
+	copy(v[8:], e.Value)
+
+	return v
+}
+
+// decode returns the entry of the stored value.
+func decode(v []byte) (entry, error) {
+	if len(v) < 8 {
+		return entry{}, errors.New("invalid stored value")
+	}
+
+	e := entry{Value: append([]byte{}, v[8:]...)}
+	if n := binary.BigEndian.Uint64(v); n != 0 {
+		e.Expires = time.Unix(0, int64(n))
+	}
+
+	return e, nil
+}
+
+// view runs the read transaction on the bucket.
+func (b *Bolt) view(fn func(bucket *bolt.Bucket) error) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(b.bucket))
+	})
+}
+
+// update runs the write transaction on the bucket.
+func (b *Bolt) update(fn func(bucket *bolt.Bucket) error) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(b.bucket))
+	})
+}
+
+// Get returns the value of the key or ErrNotFound.
+func (b *Bolt) Get(_ context.Context, key string) ([]byte, error) {
+	var value []byte
+
+	err := b.view(func(bucket *bolt.Bucket) error {
+		v := bucket.Get([]byte(key))
+		if v == nil {
+			return ErrNotFound
+		}
+
+		e, err := decode(v)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key, err)
+		}
+		if e.expired(b.now()) {
+			return ErrNotFound
+		}
+
+		value = e.Value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Set sets the value of the key expiring after ttl if it's positive.
+func (b *Bolt) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	return b.update(func(bucket *bolt.Bucket) error {
+		return bucket.Put([]byte(key), encode(newEntry(value, ttl, b.now())))
+	})
+}
+
+// Delete deletes the key.
+func (b *Bolt) Delete(_ context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	return b.update(func(bucket *bolt.Bucket) error {
+		return bucket.Delete([]byte(key))
+	})
+}
+
+// Keys returns the sorted keys with the prefix which have not expired.
+func (b *Bolt) Keys(_ context.Context, prefix string) ([]string, error) {
+	result := []string{}
+
+	err := b.view(func(bucket *bolt.Bucket) error {
+		now := b.now()
+
+		c := bucket.Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+			e, err := decode(v)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+			if !e.expired(now) {
+				result = append(result, string(k))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Purge deletes the expired keys and returns their number.
+func (b *Bolt) Purge() (int, error) {
+	n := 0
+
+	err := b.update(func(bucket *bolt.Bucket) error {
+		now := b.now()
+
+		var expired [][]byte
+
+		if err := bucket.ForEach(func(k, v []byte) error {
+			if e, err := decode(v); err == nil && e.expired(now) {
+				expired = append(expired, append([]byte{}, k...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		n = len(expired)
+		return nil
+	})
+
+	return n, err
+}
+
+// Close closes the store and the database opened by OpenBolt.
+func (b *Bolt) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.closed = true
+
+	if b.owned {
+		return b.db.Close()
+	}
+
+	return nil
+}
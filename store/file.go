@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/whois-api-llc/brand-alert-go/internal/atomicfile"
+)
+
+// File is the Store keeping the values in memory and writing them to the
+// JSON file atomically on every change. It suits small states of a single
+// process.
+type File struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[string]entry
+	closed  bool
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+var _ Store = &File{}
+
+// OpenFile opens the JSON file store creating it on the first change if the
+// file does not exist.
+func OpenFile(path string) (*File, error) {
+	f := &File{path: path, entries: make(map[string]entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read store: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &f.entries); err != nil {
+		return nil, fmt.Errorf("cannot parse store %s: %w", path, err)
+	}
+
+	if f.entries == nil {
+		f.entries = make(map[string]entry)
+	}
+
+	return f, nil
+}
+
+// now returns the current time.
+func (f *File) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+	return time.Now()
+}
+
+// live returns the copy of the non-expired entries. It must be called with the lock held.
+func (f *File) live() map[string]entry {
+	now := f.now()
+	entries := make(map[string]entry, len(f.entries))
+
+	for k, e := range f.entries {
+		if !e.expired(now) {
+			entries[k] = e
+		}
+	}
+
+	return entries
+}
+
+// save writes the entries to the file and replaces the current ones only if
+// the write succeeds, so the memory never diverges from the file. It must be
+// called with the lock held.
+func (f *File) save(entries map[string]entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("cannot marshal store: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(f.path, data, 0o600); err != nil {
+		return fmt.Errorf("cannot write store: %w", err)
+	}
+
+	f.entries = entries
+
+	return nil
+}
+
+// Get returns the value of the key or ErrNotFound.
+func (f *File) Get(_ context.Context, key string) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.closed {
+		return nil, ErrClosed
+	}
+
+	e, ok := f.entries[key]
+	if !ok || e.expired(f.now()) {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte{}, e.Value...), nil
+}
+
+// Set sets the value of the key expiring after ttl if it's positive and
+// writes the file.
+func (f *File) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	entries := f.live()
+	entries[key] = newEntry(value, ttl, f.now())
+
+	return f.save(entries)
+}
+
+// Delete deletes the key and writes the file.
+func (f *File) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	if _, ok := f.entries[key]; !ok {
+		return nil
+	}
+
+	entries := f.live()
+	delete(entries, key)
+
+	return f.save(entries)
+}
+
+// Keys returns the sorted keys with the prefix which have not expired.
+func (f *File) Keys(_ context.Context, prefix string) ([]string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.closed {
+		return nil, ErrClosed
+	}
+
+	return keys(f.entries, prefix, f.now()), nil
+}
+
+// Close closes the store. All changes are already written.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	f.entries = nil
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is the Store keeping the values in memory.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+	closed  bool
+
+	// Now returns the current time. If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+var _ Store = &Memory{}
+
+// NewMemory creates the empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]entry)}
+}
+
+// now returns the current time.
+func (m *Memory) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// Get returns the value of the key or ErrNotFound.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	e, ok := m.entries[key]
+	if !ok || e.expired(m.now()) {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte{}, e.Value...), nil
+}
+
+// Set sets the value of the key expiring after ttl if it's positive.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	m.entries[key] = newEntry(value, ttl, m.now())
+
+	return nil
+}
+
+// Delete deletes the key.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+// Keys returns the sorted keys with the prefix which have not expired.
+func (m *Memory) Keys(_ context.Context, prefix string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	return keys(m.entries, prefix, m.now()), nil
+}
+
+// Purge deletes the expired keys and returns their number.
+func (m *Memory) Purge() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	n := 0
+	for k, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, k)
+			n++
+		}
+	}
+
+	return n
+}
+
+// Close closes the store.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	m.entries = nil
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package store defines the key/value storage with expiration used by
+// stateful brand-alert features, and ships in-memory, JSON file and bbolt
+// implementations. Third-party backends can be checked with the storetest
+// conformance suite. The daemon keeps the state of its brands in Store if
+// daemon.Params.Store is set.
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when the key does not exist or has expired.
+var ErrNotFound = errors.New("key not found")
+
+// Store is the key/value storage with expiration. Implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Get returns the value of the key or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set sets the value of the key. The key expires after ttl if it's
+	// positive, otherwise it never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete deletes the key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Keys returns the sorted keys with the prefix which have not expired.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+
+	// Close releases the resources of the store.
+	Close() error
+}
+
+// ErrClosed is returned by the operations of a closed store.
+var ErrClosed = errors.New("store is closed")
+
+// entry is the stored value with its expiration time.
+type entry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// newEntry creates the entry expiring after ttl.
+func newEntry(value []byte, ttl time.Duration, now time.Time) entry {
+	e := entry{Value: append([]byte{}, value...)}
+	if ttl > 0 {
+		e.Expires = now.Add(ttl)
+	}
+
+	return e
+}
+
+// expired reports whether the entry has expired.
+func (e entry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// validateKey returns an error if the key is empty.
+func validateKey(key string) error {
+	if key == "" {
+		return errors.New("key can not be empty")
+	}
+
+	return nil
+}
+
+// keys returns the sorted keys of the non-expired entries with the prefix.
+func keys(entries map[string]entry, prefix string, now time.Time) []string {
+	result := []string{}
+
+	for k, e := range entries {
+		if strings.HasPrefix(k, prefix) && !e.expired(now) {
+			result = append(result, k)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,215 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/whois-api-llc/brand-alert-go/store"
+	"github.com/whois-api-llc/brand-alert-go/store/storetest"
+	bolt "go.etcd.io/bbolt"
+)
+
+// TestMemory runs the conformance tests of the Memory store.
+func TestMemory(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, now func() time.Time) store.Store {
+		s := store.NewMemory()
+		s.Now = now
+		return s
+	})
+}
+
+// TestFile runs the conformance tests of the File store.
+func TestFile(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, now func() time.Time) store.Store {
+		s, err := store.OpenFile(filepath.Join(t.TempDir(), "store.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Now = now
+		return s
+	})
+}
+
+// TestBolt runs the conformance tests of the Bolt store.
+func TestBolt(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, now func() time.Time) store.Store {
+		s, err := store.OpenBolt(filepath.Join(t.TempDir(), "store.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Now = now
+		return s
+	})
+}
+
+// TestPersistence tests reopening the persistent stores.
+func TestPersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	db, err := bolt.Open(filepath.Join(dir, "shared.db"), 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name string
+		open func() (store.Store, error)
+	}{
+		{
+			name: "file",
+			open: func() (store.Store, error) {
+				s, err := store.OpenFile(filepath.Join(dir, "store.json"))
+				if err == nil {
+					s.Now = clock
+				}
+				return s, err
+			},
+		},
+		{
+			name: "bolt",
+			open: func() (store.Store, error) {
+				s, err := store.OpenBolt(filepath.Join(dir, "store.db"))
+				if err == nil {
+					s.Now = clock
+				}
+				return s, err
+			},
+		},
+		{
+			name: "shared bolt",
+			open: func() (store.Store, error) {
+				s, err := store.NewBolt(db, "state")
+				if err == nil {
+					s.Now = clock
+				}
+				return s, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			s, err := tt.open()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Set(ctx, "permanent", []byte("1"), 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Set(ctx, "expiring", []byte("2"), time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			now = now.Add(30 * time.Minute)
+
+			if s, err = tt.open(); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			if keys, err := s.Keys(ctx, ""); err != nil || len(keys) != 2 {
+				t.Errorf("Keys() after reopen got = %v, %v", keys, err)
+			}
+
+			now = now.Add(time.Hour)
+
+			if _, err := s.Get(ctx, "expiring"); err != store.ErrNotFound {
+				t.Errorf("Get() of expired key error = %v", err)
+			}
+			if v, err := s.Get(ctx, "permanent"); err != nil || string(v) != "1" {
+				t.Errorf("Get() got = %q, %v", v, err)
+			}
+		})
+	}
+}
+
+// TestPurge tests purging the expired keys.
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	memory := store.NewMemory()
+	memory.Now = func() time.Time { return now }
+
+	bolts, err := store.OpenBolt(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bolts.Close()
+	bolts.Now = memory.Now
+
+	for _, s := range []store.Store{memory, bolts} {
+		_ = s.Set(ctx, "a", nil, time.Minute)
+		_ = s.Set(ctx, "b", nil, time.Hour)
+		_ = s.Set(ctx, "c", nil, 0)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if n := memory.Purge(); n != 1 {
+		t.Errorf("Memory.Purge() got = %v", n)
+	}
+	if n, err := bolts.Purge(); err != nil || n != 1 {
+		t.Errorf("Bolt.Purge() got = %v, %v", n, err)
+	}
+}
+
+// TestFileWriteError tests that the File store is not changed if the file
+// can not be written.
+func TestFileWriteError(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "store")
+
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := store.OpenFile(filepath.Join(dir, "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time { return now }
+
+	if err := s.Set(ctx, "expiring", []byte("1"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(ctx, "permanent", []byte("2"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Hour)
+
+	if err := s.Set(ctx, "new", []byte("3"), 0); err == nil {
+		t.Error("Set() expected error")
+	}
+	if err := s.Delete(ctx, "permanent"); err == nil {
+		t.Error("Delete() expected error")
+	}
+
+	// The expired entry is still in memory as it is in the file.
+	now = now.Add(-time.Hour)
+
+	if keys, err := s.Keys(ctx, ""); err != nil || len(keys) != 2 || keys[0] != "expiring" || keys[1] != "permanent" {
+		t.Errorf("Keys() got = %v, %v", keys, err)
+	}
+}
@@ -0,0 +1,250 @@
+// Package storetest is the conformance test suite of store.Store
+// implementations:
+//
+//	func TestStore(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T, now func() time.Time) store.Store {
+//			s := mybackend.New(...)
+//			s.Now = now
+//			return s
+//		})
+//	}
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/whois-api-llc/brand-alert-go/store"
+)
+
+// ttl is the time to live of expiring keys in the tests.
+const ttl = time.Minute
+
+// clock is the fake clock of the tests.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the current fake time.
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Add advances the fake time by d.
+func (c *clock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Run runs the conformance tests. The open function must return a new empty
+// store for every test using now as the current time; Run closes it.
+func Run(t *testing.T, open func(t *testing.T, now func() time.Time) store.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s store.Store, c *clock)
+	}{
+		{name: "NotFound", test: testNotFound},
+		{name: "SetGet", test: testSetGet},
+		{name: "EmptyKey", test: testEmptyKey},
+		{name: "Delete", test: testDelete},
+		{name: "Isolation", test: testIsolation},
+		{name: "Keys", test: testKeys},
+		{name: "TTL", test: testTTL},
+		{name: "Concurrency", test: testConcurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &clock{now: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)}
+
+			s := open(t, c.Now)
+			defer s.Close()
+
+			tt.test(t, s, c)
+		})
+	}
+
+	t.Run("Close", func(t *testing.T) {
+		s := open(t, time.Now)
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		if _, err := s.Get(context.Background(), "key"); err == nil || errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Get() after Close() error = %v, want a non-ErrNotFound error", err)
+		}
+	})
+}
+
+// mustSet sets the key failing the test on errors.
+func mustSet(t *testing.T, s store.Store, key, value string, ttl time.Duration) {
+	t.Helper()
+
+	if err := s.Set(context.Background(), key, []byte(value), ttl); err != nil {
+		t.Fatalf("Set(%q) error = %v", key, err)
+	}
+}
+
+// checkGet checks the value of the key, empty if it must not be found.
+func checkGet(t *testing.T, s store.Store, key, want string) {
+	t.Helper()
+
+	got, err := s.Get(context.Background(), key)
+	if want == "" {
+		if !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Get(%q) = %q, %v, want ErrNotFound", key, got, err)
+		}
+		return
+	}
+
+	if err != nil || string(got) != want {
+		t.Errorf("Get(%q) = %q, %v, want %q", key, got, err, want)
+	}
+}
+
+// checkKeys checks the keys with the prefix.
+func checkKeys(t *testing.T, s store.Store, prefix string, want []string) {
+	t.Helper()
+
+	got, err := s.Keys(context.Background(), prefix)
+	if err != nil {
+		t.Fatalf("Keys(%q) error = %v", prefix, err)
+	}
+
+	if len(got) != 0 || len(want) != 0 {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Keys(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+}
+
+func testNotFound(t *testing.T, s store.Store, _ *clock) {
+	checkGet(t, s, "missing", "")
+}
+
+func testSetGet(t *testing.T, s store.Store, _ *clock) {
+	mustSet(t, s, "brand/whois", "first", 0)
+	checkGet(t, s, "brand/whois", "first")
+
+	mustSet(t, s, "brand/whois", "second", 0)
+	checkGet(t, s, "brand/whois", "second")
+
+	mustSet(t, s, "binary", "\x00\xff\n", 0)
+	checkGet(t, s, "binary", "\x00\xff\n")
+
+	if err := s.Set(context.Background(), "empty", nil, 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, err := s.Get(context.Background(), "empty"); err != nil || len(got) != 0 {
+		t.Errorf("Get() of empty value = %q, %v", got, err)
+	}
+}
+
+func testEmptyKey(t *testing.T, s store.Store, _ *clock) {
+	if err := s.Set(context.Background(), "", []byte("value"), 0); err == nil {
+		t.Error("Set() with empty key expected error")
+	}
+}
+
+func testDelete(t *testing.T, s store.Store, _ *clock) {
+	mustSet(t, s, "key", "value", 0)
+
+	if err := s.Delete(context.Background(), "key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	checkGet(t, s, "key", "")
+
+	if err := s.Delete(context.Background(), "key"); err != nil {
+		t.Errorf("Delete() of missing key error = %v", err)
+	}
+}
+
+func testIsolation(t *testing.T, s store.Store, _ *clock) {
+	value := []byte("value")
+	if err := s.Set(context.Background(), "key", value, 0); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'X'
+
+	got, err := s.Get(context.Background(), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got[0] = 'Y'
+
+	checkGet(t, s, "key", "value")
+}
+
+func testKeys(t *testing.T, s store.Store, _ *clock) {
+	for _, key := range []string{"seen/b", "seen/a", "seen/c", "state/a", "seem"} {
+		mustSet(t, s, key, "1", 0)
+	}
+
+	checkKeys(t, s, "seen/", []string{"seen/a", "seen/b", "seen/c"})
+	checkKeys(t, s, "", []string{"seem", "seen/a", "seen/b", "seen/c", "state/a"})
+	checkKeys(t, s, "other/", nil)
+}
+
+func testTTL(t *testing.T, s store.Store, c *clock) {
+	mustSet(t, s, "expiring", "value", ttl)
+	mustSet(t, s, "permanent", "value", 0)
+	mustSet(t, s, "renewed", "value", ttl)
+
+	checkGet(t, s, "expiring", "value")
+
+	// Setting the key again without TTL makes it permanent.
+	mustSet(t, s, "renewed", "value", 0)
+
+	c.Add(ttl - time.Nanosecond)
+	checkGet(t, s, "expiring", "value")
+
+	c.Add(time.Nanosecond)
+
+	checkGet(t, s, "expiring", "")
+	checkGet(t, s, "permanent", "value")
+	checkGet(t, s, "renewed", "value")
+	checkKeys(t, s, "", []string{"permanent", "renewed"})
+}
+
+func testConcurrency(t *testing.T, s store.Store, _ *clock) {
+	const workers, keys = 8, 20
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			ctx := context.Background()
+			for i := 0; i < keys; i++ {
+				key := fmt.Sprintf("worker/%d/%02d", w, i)
+				if err := s.Set(ctx, key, []byte(key), 0); err != nil {
+					t.Errorf("Set() error = %v", err)
+					return
+				}
+				if got, err := s.Get(ctx, key); err != nil || string(got) != key {
+					t.Errorf("Get(%q) = %q, %v", key, got, err)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	got, err := s.Keys(context.Background(), "worker/")
+	if err != nil || len(got) != workers*keys {
+		t.Errorf("Keys() got %d keys, %v, want %d", len(got), err, workers*keys)
+	}
+}
@@ -0,0 +1,162 @@
+package suppress
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the format of the rules file.
+type Format string
+
+// List of supported formats.
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+var _ = []Format{
+	JSON,
+	YAML,
+}
+
+// expiresFormats is the list of accepted formats of the expiration time.
+var expiresFormats = []string{
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// ruleEntry is the rule representation in the rules file.
+type ruleEntry struct {
+	Type    Type   `json:"type" yaml:"type"`
+	Value   string `json:"value" yaml:"value"`
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+}
+
+// rulesFile is the rules file structure.
+type rulesFile struct {
+	Rules []ruleEntry `json:"rules" yaml:"rules"`
+}
+
+// Parse parses the rules file content in the specified format.
+func Parse(data []byte, format Format) ([]Rule, error) {
+	var file rulesFile
+
+	switch format {
+	case JSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("cannot parse rules: %w", err)
+		}
+	case YAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("cannot parse rules: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf(`cannot parse rules: unknown format "%s"`, format)
+	}
+
+	rules := make([]Rule, 0, len(file.Rules))
+
+	for i, entry := range file.Rules {
+		rule := Rule{
+			Type:   entry.Type,
+			Value:  entry.Value,
+			Reason: entry.Reason,
+		}
+
+		if entry.Expires != "" {
+			expires, err := ParseExpires(entry.Expires)
+			if err != nil {
+				return nil, &RuleError{i, err.Error()}
+			}
+			rule.Expires = expires
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Marshal encodes rules in the specified format.
+func Marshal(rules []Rule, format Format) ([]byte, error) {
+	file := rulesFile{
+		Rules: make([]ruleEntry, 0, len(rules)),
+	}
+
+	for _, rule := range rules {
+		entry := ruleEntry{
+			Type:   rule.Type,
+			Value:  rule.Value,
+			Reason: rule.Reason,
+		}
+		if !rule.Expires.IsZero() {
+			entry.Expires = rule.Expires.Format(time.RFC3339)
+		}
+		file.Rules = append(file.Rules, entry)
+	}
+
+	switch format {
+	case JSON:
+		return json.MarshalIndent(file, "", "  ")
+	case YAML:
+		return yaml.Marshal(file)
+	}
+
+	return nil, fmt.Errorf(`cannot encode rules: unknown format "%s"`, format)
+}
+
+// Load loads the rules file and creates List. The format is detected by
+// the file extension: .json, .yaml or .yml.
+func Load(path string) (*List, error) {
+	format, err := formatOf(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read rules: %w", err)
+	}
+
+	rules, err := Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(rules...)
+}
+
+// formatOf returns the file format by its extension.
+func formatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, nil
+	case ".yaml", ".yml":
+		return YAML, nil
+	}
+
+	return "", fmt.Errorf(`cannot detect format of "%s"`, path)
+}
+
+// ParseExpires parses the rule expiration time: a date or an RFC 3339 time.
+func ParseExpires(s string) (time.Time, error) {
+	for _, layout := range expiresFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf(`invalid expiration time "%s"`, s)
+}
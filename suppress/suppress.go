@@ -0,0 +1,272 @@
+// Package suppress filters known-good domains, e.g. brand-owned, partner
+// domains or triaged false positives, out of Brand Alert API responses.
+package suppress
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+	"github.com/whois-api-llc/brand-alert-go/internal/punycode"
+	"github.com/whois-api-llc/brand-alert-go/publicsuffix"
+)
+
+// Type is the type of the suppression rule.
+type Type string
+
+// List of possible rule types.
+const (
+	// Exact matches the domain name exactly.
+	Exact Type = "exact"
+
+	// Suffix matches the domain name and all its subdomains.
+	Suffix Type = "suffix"
+
+	// Regexp matches the domain names by the regular expression. Internationalized
+	// domain names are matched in their ASCII (Punycode) form.
+	Regexp Type = "regex"
+
+	// Registrable matches all domain names having the same registrable domain.
+	Registrable Type = "registrable"
+)
+
+var _ = []Type{
+	Exact,
+	Suffix,
+	Regexp,
+	Registrable,
+}
+
+// Rule is the suppression rule.
+type Rule struct {
+	// Type is the type of the rule. Possible types: exact | suffix | regex | registrable.
+	Type Type
+
+	// Value is the domain name, suffix or regular expression depending on Type.
+	Value string
+
+	// Reason explains why the matching domains are suppressed.
+	Reason string
+
+	// Expires is the time the rule stops working. Zero means never.
+	Expires time.Time
+}
+
+// Expired reports whether the rule is expired at the given time.
+func (r Rule) Expired(now time.Time) bool {
+	return !r.Expires.IsZero() && !now.Before(r.Expires)
+}
+
+// String returns the rule as a string.
+func (r Rule) String() string {
+	return string(r.Type) + ":" + r.Value
+}
+
+// RuleError is returned when the rule is invalid.
+type RuleError struct {
+	// Index is the zero-based index of the rule.
+	Index int
+
+	// Message is the error message.
+	Message string
+}
+
+// Error returns error message as a string.
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("invalid rule #%d: %s", e.Index+1, e.Message)
+}
+
+// compiledRule is the rule prepared for matching.
+type compiledRule struct {
+	Rule
+
+	value string
+	re    *regexp.Regexp
+}
+
+// match reports whether the domain name matches the rule.
+func (r *compiledRule) match(domain string) bool {
+	switch r.Type {
+	case Exact:
+		return domain == r.value
+	case Suffix:
+		if strings.HasPrefix(r.value, ".") {
+			return strings.HasSuffix(domain, r.value)
+		}
+		return domain == r.value || strings.HasSuffix(domain, "."+r.value)
+	case Regexp:
+		return r.re.MatchString(domain)
+	case Registrable:
+		return publicsuffix.RegistrableDomain(domain) == r.value
+	}
+
+	return false
+}
+
+// List is the list of suppression rules.
+type List struct {
+	rules []*compiledRule
+
+	// Now returns the current time used to check rule expiration.
+	// If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+// New creates List with specified rules.
+func New(rules ...Rule) (*List, error) {
+	list := &List{
+		rules: make([]*compiledRule, 0, len(rules)),
+	}
+
+	for i, rule := range rules {
+		compiled := &compiledRule{
+			Rule:  rule,
+			value: normalize(rule.Value),
+		}
+
+		if rule.Value == "" {
+			return nil, &RuleError{i, "value can not be empty"}
+		}
+
+		switch rule.Type {
+		case Exact, Suffix:
+		case Registrable:
+			compiled.value = publicsuffix.RegistrableDomain(rule.Value)
+			if compiled.value == "" {
+				return nil, &RuleError{i, `"` + rule.Value + `" is a public suffix`}
+			}
+		case Regexp:
+			re, err := regexp.Compile(rule.Value)
+			if err != nil {
+				return nil, &RuleError{i, err.Error()}
+			}
+			compiled.re = re
+		default:
+			return nil, &RuleError{i, `unknown type "` + string(rule.Type) + `"`}
+		}
+
+		list.rules = append(list.rules, compiled)
+	}
+
+	return list, nil
+}
+
+// Rules returns the list of rules.
+func (l *List) Rules() []Rule {
+	rules := make([]Rule, 0, len(l.rules))
+	for _, r := range l.rules {
+		rules = append(rules, r.Rule)
+	}
+
+	return rules
+}
+
+// now returns the current time.
+func (l *List) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
+
+// match returns the index of the first active rule matching the domain name.
+func (l *List) match(domain string, now time.Time) int {
+	domain = normalize(domain)
+
+	for i, r := range l.rules {
+		if !r.Expired(now) && r.match(domain) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Match returns the first active rule matching the domain name.
+func (l *List) Match(domainName string) (Rule, bool) {
+	if i := l.match(domainName, l.now()); i >= 0 {
+		return l.rules[i].Rule, true
+	}
+
+	return Rule{}, false
+}
+
+// RuleReport is the number of domains suppressed by the rule.
+type RuleReport struct {
+	// Rule is the suppression rule.
+	Rule Rule
+
+	// Expired is true if the rule was skipped as expired.
+	Expired bool
+
+	// Suppressed is the number of domains suppressed by the rule.
+	Suppressed int
+}
+
+// Report is the result of filtering.
+type Report struct {
+	// Total is the number of domains before filtering.
+	Total int
+
+	// Suppressed is the number of suppressed domains.
+	Suppressed int
+
+	// Rules is the per-rule report in the order of rules.
+	Rules []RuleReport
+}
+
+// FilterItems returns the items not matching any active rule.
+func (l *List) FilterItems(items []brandalert.DomainItem) ([]brandalert.DomainItem, Report) {
+	now := l.now()
+
+	report := Report{
+		Total: len(items),
+		Rules: make([]RuleReport, len(l.rules)),
+	}
+
+	for i, r := range l.rules {
+		report.Rules[i] = RuleReport{Rule: r.Rule, Expired: r.Expired(now)}
+	}
+
+	res := make([]brandalert.DomainItem, 0, len(items))
+
+	for _, item := range items {
+		if i := l.match(item.DomainName, now); i >= 0 {
+			report.Rules[i].Suppressed++
+			report.Suppressed++
+			continue
+		}
+		res = append(res, item)
+	}
+
+	return res, report
+}
+
+// Filter returns a copy of the response without suppressed domains.
+// DomainsCount of the copy is the number of domains left.
+func (l *List) Filter(resp *brandalert.BrandAlertResponse) (*brandalert.BrandAlertResponse, Report) {
+	if resp == nil {
+		return nil, Report{}
+	}
+
+	items, report := l.FilterItems(resp.DomainsList)
+
+	return &brandalert.BrandAlertResponse{
+		DomainsList:  items,
+		DomainsCount: len(items),
+	}, report
+}
+
+// normalize returns the lower case ASCII domain name without the trailing dot,
+// so that rules and domain names in Unicode and Punycode match each other.
+func normalize(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+
+	if ascii, err := punycode.ToASCII(domain); err == nil {
+		return ascii
+	}
+
+	return domain
+}
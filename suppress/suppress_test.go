@@ -0,0 +1,269 @@
+package suppress
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	brandalert "github.com/whois-api-llc/brand-alert-go"
+)
+
+var now = time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+// TestFilter tests the Filter function.
+func TestFilter(t *testing.T) {
+	list, err := New(
+		Rule{Type: Exact, Value: "WhoisXMLAPI.com", Reason: "official"},
+		Rule{Type: Suffix, Value: "partner.net", Reason: "partner"},
+		Rule{Type: Regexp, Value: `^whois-[0-9]+\.`, Reason: "triaged"},
+		Rule{Type: Registrable, Value: "www.whois.co.uk", Reason: "partner"},
+		Rule{Type: Exact, Value: "whois.tk", Reason: "expired", Expires: now},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Now = func() time.Time { return now }
+
+	resp := &brandalert.BrandAlertResponse{
+		DomainsList: []brandalert.DomainItem{
+			{DomainName: "whoisxmlapi.com"},
+			{DomainName: "www.whoisxmlapi.com"},
+			{DomainName: "partner.net"},
+			{DomainName: "whois.partner.net"},
+			{DomainName: "whoispartner.net"},
+			{DomainName: "whois-1.com"},
+			{DomainName: "api.whois.co.uk"},
+			{DomainName: "whoisology.co.uk"},
+			{DomainName: "whois.tk"},
+		},
+		DomainsCount: 9,
+	}
+
+	got, report := list.Filter(resp)
+
+	var names []string
+	for _, item := range got.DomainsList {
+		names = append(names, item.DomainName)
+	}
+
+	want := []string{"www.whoisxmlapi.com", "whoispartner.net", "whoisology.co.uk", "whois.tk"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Filter() got = %v, want %v", names, want)
+	}
+
+	if got.DomainsCount != 4 || report.Total != 9 || report.Suppressed != 5 {
+		t.Errorf("Filter() got count = %d, report = %+v", got.DomainsCount, report)
+	}
+
+	counts := make([]int, 0, len(report.Rules))
+	for _, r := range report.Rules {
+		counts = append(counts, r.Suppressed)
+	}
+
+	if !reflect.DeepEqual(counts, []int{1, 2, 1, 1, 0}) || !report.Rules[4].Expired {
+		t.Errorf("Filter() got report = %+v", report.Rules)
+	}
+
+	if rule, ok := list.Match("sub.partner.net."); !ok || rule.Reason != "partner" {
+		t.Errorf("Match() got = %v, %v", rule, ok)
+	}
+}
+
+// TestMatchIDN tests matching internationalized domain names in Unicode and
+// Punycode forms by all rule types.
+func TestMatchIDN(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   Rule
+		domain string
+	}{
+		{
+			name:   "exact unicode rule",
+			rule:   Rule{Type: Exact, Value: "Пример.com"},
+			domain: "xn--e1afmkfd.com",
+		},
+		{
+			name:   "exact punycode rule",
+			rule:   Rule{Type: Exact, Value: "xn--e1afmkfd.com"},
+			domain: "пример.com",
+		},
+		{
+			name:   "suffix unicode rule",
+			rule:   Rule{Type: Suffix, Value: "пример.com"},
+			domain: "www.xn--e1afmkfd.com",
+		},
+		{
+			name:   "dot suffix unicode rule",
+			rule:   Rule{Type: Suffix, Value: ".пример.com"},
+			domain: "www.xn--e1afmkfd.com",
+		},
+		{
+			name:   "registrable unicode rule",
+			rule:   Rule{Type: Registrable, Value: "www.пример.com"},
+			domain: "mail.xn--e1afmkfd.com",
+		},
+		{
+			name:   "regex",
+			rule:   Rule{Type: Regexp, Value: `^xn--e1afmkfd\.com$`},
+			domain: "пример.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := New(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := list.Match(tt.domain); !ok {
+				t.Errorf("Match(%s) got = false, want true", tt.domain)
+			}
+			if _, ok := list.Match("xn--e1afmkfd.net"); ok {
+				t.Errorf("Match(xn--e1afmkfd.net) got = true, want false")
+			}
+		})
+	}
+}
+
+// TestNew tests the rules validation.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{
+			name:    "empty value",
+			rule:    Rule{Type: Exact},
+			wantErr: "invalid rule #1: value can not be empty",
+		},
+		{
+			name:    "unknown type",
+			rule:    Rule{Type: "prefix", Value: "whois"},
+			wantErr: `invalid rule #1: unknown type "prefix"`,
+		},
+		{
+			name:    "public suffix",
+			rule:    Rule{Type: Registrable, Value: "co.uk"},
+			wantErr: `invalid rule #1: "co.uk" is a public suffix`,
+		},
+		{
+			name:    "invalid regexp",
+			rule:    Rule{Type: Regexp, Value: "whois("},
+			wantErr: "invalid rule #1: error parsing regexp: missing closing ): `whois(`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.rule)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLoad tests the Load function.
+func TestLoad(t *testing.T) {
+	const rulesYAML = `rules:
+  - type: exact
+    value: whoisxmlapi.com
+    reason: official
+  - type: suffix
+    value: partner.net
+    reason: partner
+    expires: 2023-01-01
+`
+
+	const rulesJSON = `{"rules":[
+{"type":"exact","value":"whoisxmlapi.com","reason":"official"},
+{"type":"suffix","value":"partner.net","reason":"partner","expires":"2023-01-01T00:00:00Z"}]}`
+
+	want := []Rule{
+		{Type: Exact, Value: "whoisxmlapi.com", Reason: "official"},
+		{Type: Suffix, Value: "partner.net", Reason: "partner", Expires: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "rules.yaml",
+			content: rulesYAML,
+		},
+		{
+			name:    "rules.json",
+			content: rulesJSON,
+		},
+		{
+			name:    "invalid.yml",
+			content: "rules:\n  - type: exact\n    domain: whois.com\n",
+			wantErr: "cannot parse rules: yaml: unmarshal errors:\n  line 3: field domain not found in type suppress.ruleEntry",
+		},
+		{
+			name:    "expires.json",
+			content: `{"rules":[{"type":"exact","value":"whois.com","expires":"tomorrow"}]}`,
+			wantErr: `invalid rule #1: invalid expiration time "tomorrow"`,
+		},
+		{
+			name:    "rules.txt",
+			content: rulesJSON,
+			wantErr: `cannot detect format of "` + filepath.Join(dir, "rules.txt") + `"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			list, err := Load(path)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if got := list.Rules(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Load() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestMarshal tests the Marshal function.
+func TestMarshal(t *testing.T) {
+	rules := []Rule{
+		{Type: Regexp, Value: `^whois\d+\.com$`, Reason: "triaged", Expires: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, format := range []Format{JSON, YAML} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Marshal(rules, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Parse(data, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, rules) {
+				t.Errorf("Parse() got = %v, want %v", got, rules)
+			}
+		})
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
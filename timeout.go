@@ -0,0 +1,192 @@
+package brandalert
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPreviewTimeout is the default deadline of Preview requests.
+	DefaultPreviewTimeout = 30 * time.Second
+
+	// DefaultPurchaseTimeout is the default deadline of Purchase and RawData requests.
+	DefaultPurchaseTimeout = 5 * time.Minute
+)
+
+// TimeoutPhase is the phase of the request which timed out.
+type TimeoutPhase string
+
+const (
+	// TimeoutConnect means the connection was not established in time.
+	TimeoutConnect TimeoutPhase = "connect"
+
+	// TimeoutHeader means the response headers were not received in time.
+	TimeoutHeader TimeoutPhase = "header"
+
+	// TimeoutBody means the response body was not read in time.
+	TimeoutBody TimeoutPhase = "body"
+
+	// TimeoutDeadline means the deadline of the request context was exceeded.
+	TimeoutDeadline TimeoutPhase = "deadline"
+)
+
+// TimeoutError is returned when the request times out. It matches
+// context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	// Phase is the phase of the request which timed out.
+	Phase TimeoutPhase
+
+	// Timeout is the exceeded timeout. It's zero for deadlines set by the caller.
+	Timeout time.Duration
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns error message as a string.
+func (e *TimeoutError) Error() string {
+	if e.Timeout > 0 {
+		return "request timed out: " + string(e.Phase) + " timeout of " + e.Timeout.String() + " exceeded"
+	}
+
+	return "request timed out: " + string(e.Phase) + " exceeded"
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is context.DeadlineExceeded.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// defaultTimeoutKey is the context key of the default deadline timeout.
+type defaultTimeoutKey struct{}
+
+// withDefaultTimeout returns the context with the default deadline which is
+// reported by TimeoutError.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithValue(ctx, defaultTimeoutKey{}, timeout), timeout)
+}
+
+// withConnectTimeout returns the copy of the HTTP client limiting the dial,
+// either with its own dialer or the default one, and the TLS handshake by the
+// timeout. Clients with a custom non-*http.Transport transport are returned as is.
+func withConnectTimeout(client *http.Client, timeout time.Duration) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	transport = transport.Clone()
+
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		conn, err := dial(dialCtx, network, addr)
+		if err != nil && ctx.Err() == nil && errors.Is(dialCtx.Err(), context.DeadlineExceeded) {
+			return nil, &TimeoutError{Phase: TimeoutConnect, Timeout: timeout, Err: err}
+		}
+
+		return conn, err
+	}
+	transport.TLSHandshakeTimeout = timeout
+
+	c := *client
+	c.Transport = transport
+
+	return &c
+}
+
+// phaseTimer cancels the request when the current phase exceeds its timeout.
+type phaseTimer struct {
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	expired TimeoutPhase
+	timeout time.Duration
+
+	// connect is the connect timeout reported by the errors.
+	connect time.Duration
+}
+
+// newPhaseTimer creates the timer calling cancel on expiration.
+func newPhaseTimer(cancel context.CancelFunc, connect time.Duration) *phaseTimer {
+	return &phaseTimer{cancel: cancel, connect: connect}
+}
+
+// start starts the phase stopping the previous one. Zero timeout means no limit.
+func (t *phaseTimer) start(phase TimeoutPhase, timeout time.Duration) {
+	t.stop()
+
+	if timeout <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timer = time.AfterFunc(timeout, func() {
+		t.mu.Lock()
+		t.expired, t.timeout = phase, timeout
+		t.mu.Unlock()
+
+		t.cancel()
+	})
+}
+
+// stop stops the current phase.
+func (t *phaseTimer) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
+
+// wrap returns *TimeoutError if err is caused by a timeout, otherwise err.
+func (t *phaseTimer) wrap(ctx context.Context, err error) error {
+	t.mu.Lock()
+	expired, timeout := t.expired, t.timeout
+	t.mu.Unlock()
+
+	if expired != "" {
+		return &TimeoutError{Phase: expired, Timeout: timeout, Err: err}
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		timeout, _ := ctx.Value(defaultTimeoutKey{}).(time.Duration)
+		return &TimeoutError{Phase: TimeoutDeadline, Timeout: timeout, Err: err}
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return err
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout() {
+		return &TimeoutError{Phase: TimeoutConnect, Timeout: t.connect, Err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,223 @@
+package brandalert
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTimeouts tests the timeouts of ClientParams.
+func TestTimeouts(t *testing.T) {
+	const body = `{"domainsCount":1}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/slow-header":
+			time.Sleep(100 * time.Millisecond)
+		case "/slow-body":
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		params      ClientParams
+		ctxTimeout  time.Duration
+		wantPhase   TimeoutPhase
+		wantTimeout time.Duration
+		wantErr     string
+	}{
+		{
+			name: "fast",
+			path: "/fast",
+			params: ClientParams{
+				HeaderTimeout: 50 * time.Millisecond,
+				BodyTimeout:   50 * time.Millisecond,
+			},
+		},
+		{
+			name:        "header",
+			path:        "/slow-header",
+			params:      ClientParams{HeaderTimeout: 20 * time.Millisecond},
+			wantPhase:   TimeoutHeader,
+			wantTimeout: 20 * time.Millisecond,
+			wantErr:     "cannot execute request: request timed out: header timeout of 20ms exceeded",
+		},
+		{
+			name: "body",
+			path: "/slow-body",
+			params: ClientParams{
+				HeaderTimeout: 50 * time.Millisecond,
+				BodyTimeout:   20 * time.Millisecond,
+			},
+			wantPhase:   TimeoutBody,
+			wantTimeout: 20 * time.Millisecond,
+			wantErr:     "cannot read response: request timed out: body timeout of 20ms exceeded",
+		},
+		{
+			name:        "default deadline",
+			path:        "/slow-header",
+			params:      ClientParams{PreviewTimeout: 20 * time.Millisecond},
+			wantPhase:   TimeoutDeadline,
+			wantTimeout: 20 * time.Millisecond,
+			wantErr:     "cannot execute request: request timed out: deadline timeout of 20ms exceeded",
+		},
+		{
+			name:       "caller deadline",
+			path:       "/slow-header",
+			params:     ClientParams{PreviewTimeout: 20 * time.Millisecond},
+			ctxTimeout: time.Second,
+		},
+		{
+			name:       "caller deadline exceeded",
+			path:       "/slow-header",
+			ctxTimeout: 20 * time.Millisecond,
+			wantPhase:  TimeoutDeadline,
+			wantErr:    "cannot execute request: request timed out: deadline exceeded",
+		},
+		{
+			name:   "no deadline",
+			path:   "/slow-header",
+			params: ClientParams{PreviewTimeout: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiURL, err := url.Parse(server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params := tt.params
+			params.HTTPClient = server.Client()
+			params.BrandAlertBaseURL = apiURL
+
+			client := NewClient(apiKey, params)
+
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+
+			_, _, err = client.Preview(ctx, &SearchTerms{"whois"}, nil)
+			checkErr(t, err, tt.wantErr)
+
+			if tt.wantPhase == "" {
+				return
+			}
+
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("Preview() error = %v, want *TimeoutError", err)
+			}
+			if timeoutErr.Phase != tt.wantPhase || timeoutErr.Timeout != tt.wantTimeout {
+				t.Errorf("TimeoutError got = %v/%v, want %v/%v",
+					timeoutErr.Phase, timeoutErr.Timeout, tt.wantPhase, tt.wantTimeout)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Preview() error = %v, want context.DeadlineExceeded", err)
+			}
+		})
+	}
+}
+
+// TestConnectTimeout tests that ConnectTimeout limits the custom dialer of the
+// caller without modifying the HTTP client.
+func TestConnectTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"domainsCount":1}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		dials int32
+		hang  int32
+	)
+
+	dialer := &net.Dialer{}
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+
+		if atomic.LoadInt32(&hang) != 0 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	transport := &http.Transport{DialContext: dial}
+	httpClient := &http.Client{Transport: transport}
+
+	client := NewClient(apiKey, ClientParams{
+		HTTPClient:        httpClient,
+		BrandAlertBaseURL: apiURL,
+		ConnectTimeout:    20 * time.Millisecond,
+	})
+
+	if httpClient.Transport != transport || transport.TLSHandshakeTimeout != 0 {
+		t.Error("NewClient() modified the HTTP client")
+	}
+
+	got, ok := client.client.Transport.(*http.Transport)
+	if !ok || got == transport || got.TLSHandshakeTimeout != 20*time.Millisecond {
+		t.Errorf("NewClient() transport = %v, want the copy with the timeouts", client.client.Transport)
+	}
+
+	_, _, err = client.Preview(context.Background(), &SearchTerms{"whois"}, nil)
+	checkErr(t, err, "")
+	if atomic.LoadInt32(&dials) != 1 {
+		t.Errorf("custom dialer called %d times, want 1", dials)
+	}
+
+	got.CloseIdleConnections()
+	atomic.StoreInt32(&hang, 1)
+
+	_, _, err = client.Preview(context.Background(), &SearchTerms{"whois"}, nil)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Phase != TimeoutConnect || timeoutErr.Timeout != 20*time.Millisecond {
+		t.Errorf("Preview() error = %v, want connect timeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Preview() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestDefaultTimeout tests the defaultTimeout function.
+func TestDefaultTimeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{0, DefaultPreviewTimeout},
+		{-1, 0},
+		{time.Second, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := defaultTimeout(tt.timeout, DefaultPreviewTimeout); got != tt.want {
+			t.Errorf("defaultTimeout(%v) = %v, want %v", tt.timeout, got, tt.want)
+		}
+	}
+}